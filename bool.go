@@ -0,0 +1,47 @@
+package goenv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BoolWords are the words, besides those accepted by strconv.ParseBool, that are parsed as true or false.
+// Words are matched case-insensitively.
+type BoolWords struct {
+	True  []string
+	False []string
+}
+
+// DefaultBoolWords are used by Bool, ParseBool and Struct unless WithBoolWords or WithStrictBool is used.
+// Set it to BoolWords{} to only accept the values strconv.ParseBool does.
+var DefaultBoolWords = BoolWords{
+	True:  []string{"yes", "y", "on", "enabled", "enable"},
+	False: []string{"no", "n", "off", "disabled", "disable"},
+}
+
+// ParseBool parses s as a bool. It accepts the values strconv.ParseBool does, and the words of DefaultBoolWords,
+// such as "yes", "off" or "Enabled".
+func ParseBool(s string) (bool, error) {
+	return DefaultBoolWords.Parse(s)
+}
+
+// Parse parses s as a bool. It accepts the values strconv.ParseBool does, and the words of w.
+func (w BoolWords) Parse(s string) (bool, error) {
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b, nil
+	}
+
+	for _, word := range w.True {
+		if strings.EqualFold(s, word) {
+			return true, nil
+		}
+	}
+	for _, word := range w.False {
+		if strings.EqualFold(s, word) {
+			return false, nil
+		}
+	}
+
+	return false, fmt.Errorf("invalid bool %q", s)
+}
@@ -0,0 +1,70 @@
+package goenv
+
+import (
+	"testing"
+)
+
+func TestBoolWordsParse(t *testing.T) {
+	custom := BoolWords{True: []string{"ja"}, False: []string{"nej"}}
+
+	tests := []struct {
+		name  string
+		words BoolWords
+		value string
+		want  bool
+		fail  bool
+	}{
+		{name: "strconv true", words: DefaultBoolWords, value: "true", want: true},
+		{name: "strconv 0", words: DefaultBoolWords, value: "0", want: false},
+		{name: "yes", words: DefaultBoolWords, value: "yes", want: true},
+		{name: "Y", words: DefaultBoolWords, value: "Y", want: true},
+		{name: "On", words: DefaultBoolWords, value: "On", want: true},
+		{name: "disabled", words: DefaultBoolWords, value: "disabled", want: false},
+		{name: "maybe", words: DefaultBoolWords, value: "maybe", fail: true},
+		{name: "custom ja", words: custom, value: "JA", want: true},
+		{name: "custom nej", words: custom, value: "nej", want: false},
+		{name: "custom yes", words: custom, value: "yes", fail: true},
+		{name: "strict yes", words: BoolWords{}, value: "yes", fail: true},
+		{name: "strict true", words: BoolWords{}, value: "true", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.words.Parse(tt.value)
+			if tt.fail {
+				if err == nil {
+					t.Errorf("Parse(%q) = %v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Parse(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructBoolWords(t *testing.T) {
+	env := map[string]string{"DEBUG": "yes", "FEATURE_X": "ja"}
+
+	type config struct {
+		Debug bool `goenv:"DEBUG"`
+	}
+
+	var lenient config
+	if err := Struct(&lenient, WithEnvironment(env)); err != nil || !lenient.Debug {
+		t.Errorf("Struct() = %+v, %v, want Debug true", lenient, err)
+	}
+
+	var strict config
+	if err := Struct(&strict, WithEnvironment(env), WithStrictBool()); err == nil {
+		t.Errorf("Struct() error = nil, want error with WithStrictBool")
+	}
+
+	var custom struct {
+		FeatureX bool `goenv:"FEATURE_X"`
+	}
+	words := BoolWords{True: []string{"ja"}, False: []string{"nej"}}
+	if err := Struct(&custom, WithEnvironment(env), WithBoolWords(words)); err != nil || !custom.FeatureX {
+		t.Errorf("Struct() = %+v, %v, want FeatureX true", custom, err)
+	}
+}
@@ -0,0 +1,110 @@
+package goenv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes, parsed from human friendly values such as "512MiB" or "2GB".
+type ByteSize uint64
+
+// SI units, in powers of 1000.
+const (
+	Byte     ByteSize = 1
+	Kilobyte ByteSize = 1000 * Byte
+	Megabyte ByteSize = 1000 * Kilobyte
+	Gigabyte ByteSize = 1000 * Megabyte
+	Terabyte ByteSize = 1000 * Gigabyte
+	Petabyte ByteSize = 1000 * Terabyte
+)
+
+// IEC units, in powers of 1024.
+const (
+	Kibibyte ByteSize = 1024 * Byte
+	Mebibyte ByteSize = 1024 * Kibibyte
+	Gibibyte ByteSize = 1024 * Mebibyte
+	Tebibyte ByteSize = 1024 * Gibibyte
+	Pebibyte ByteSize = 1024 * Tebibyte
+)
+
+var byteUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   Kilobyte,
+	"kb":  Kilobyte,
+	"m":   Megabyte,
+	"mb":  Megabyte,
+	"g":   Gigabyte,
+	"gb":  Gigabyte,
+	"t":   Terabyte,
+	"tb":  Terabyte,
+	"p":   Petabyte,
+	"pb":  Petabyte,
+	"kib": Kibibyte,
+	"mib": Mebibyte,
+	"gib": Gibibyte,
+	"tib": Tebibyte,
+	"pib": Pebibyte,
+}
+
+// formatUnits are tried in order by ByteSize.String.
+var formatUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"PiB", Pebibyte}, {"PB", Petabyte},
+	{"TiB", Tebibyte}, {"TB", Terabyte},
+	{"GiB", Gibibyte}, {"GB", Gigabyte},
+	{"MiB", Mebibyte}, {"MB", Megabyte},
+	{"KiB", Kibibyte}, {"KB", Kilobyte},
+}
+
+// ParseByteSize parses a byte size such as "512MiB", "2GB", "1.5 GiB" or "1024".
+//
+// SI units (KB, MB, GB, TB, PB, or K, M, G, T, P) are powers of 1000, IEC units (KiB, MiB, GiB,
+// TiB, PiB) are powers of 1024, and a number without a unit is a number of bytes. Units are not
+// case sensitive.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end == -1 {
+		end = len(s)
+	}
+
+	number, unit := s[:end], strings.ToLower(strings.TrimSpace(s[end:]))
+	multiplier, ok := byteUnits[unit]
+	if number == "" || !ok {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if n > uint64(^ByteSize(0)/multiplier) {
+			return 0, fmt.Errorf("byte size %q overflows", s)
+		}
+		return ByteSize(n) * multiplier, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	size := f * float64(multiplier)
+	if size >= float64(^ByteSize(0)) {
+		return 0, fmt.Errorf("byte size %q overflows", s)
+	}
+	return ByteSize(size), nil
+}
+
+// String formats b with the largest unit that divides it exactly, e.g. "512MiB" or "2GB".
+// ParseByteSize parses the result back to b.
+func (b ByteSize) String() string {
+	for _, unit := range formatUnits {
+		if b >= unit.size && b%unit.size == 0 {
+			return strconv.FormatUint(uint64(b/unit.size), 10) + unit.name
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
@@ -0,0 +1,101 @@
+package goenv
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ByteSize
+		fail  bool
+	}{
+		{value: "1024", want: 1024},
+		{value: "100B", want: 100},
+		{value: "512MiB", want: 512 * Mebibyte},
+		{value: "2GB", want: 2 * Gigabyte},
+		{value: "2gb", want: 2 * Gigabyte},
+		{value: "10k", want: 10 * Kilobyte},
+		{value: "1.5 GiB", want: 1536 * Mebibyte},
+		{value: "1PiB", want: Pebibyte},
+		{value: "", fail: true},
+		{value: "MiB", fail: true},
+		{value: "12XB", fail: true},
+		{value: "-1MB", fail: true},
+		{value: "1.2.3MB", fail: true},
+		{value: "20000000PB", fail: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.value)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("ParseByteSize(%q) = %v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %v, %v, want %v", tt.value, uint64(got), err, uint64(tt.want))
+		}
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{0, "0B"},
+		{100, "100B"},
+		{1000, "1KB"},
+		{1024, "1KiB"},
+		{512 * Mebibyte, "512MiB"},
+		{2 * Gigabyte, "2GB"},
+		{1536 * Mebibyte, "1536MiB"},
+		{1025, "1025B"},
+	}
+	for _, tt := range tests {
+		if got := tt.size.String(); got != tt.want {
+			t.Errorf("ByteSize(%d).String() = %v, want %v", uint64(tt.size), got, tt.want)
+		}
+		if parsed, err := ParseByteSize(tt.want); err != nil || parsed != tt.size {
+			t.Errorf("ParseByteSize(%q) = %v, %v, want %v", tt.want, uint64(parsed), err, uint64(tt.size))
+		}
+	}
+}
+
+func TestStructByteSize(t *testing.T) {
+	env := map[string]string{"CACHE_SIZE": "512MiB", "UPLOAD_LIMIT": "2GB"}
+
+	var cfg struct {
+		CacheSize   ByteSize `goenv:"CACHE_SIZE,max=1GiB"`
+		UploadLimit ByteSize `goenv:"UPLOAD_LIMIT,max=1GiB"`
+		Buffer      ByteSize `goenv:"BUFFER,default=64KiB"`
+	}
+	err := Struct(&cfg, WithEnvironment(env))
+	if err == nil {
+		t.Fatalf("Struct() error = nil, want error for UploadLimit above max")
+	}
+	if cfg.CacheSize != 512*Mebibyte || cfg.Buffer != 64*Kibibyte {
+		t.Errorf("cfg = %+v", cfg)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	os.Setenv("TEST_BYTES", "10MB")
+	os.Setenv("TEST_INVALID_BYTES", "ten megabytes")
+	t.Cleanup(func() {
+		os.Unsetenv("TEST_BYTES")
+		os.Unsetenv("TEST_INVALID_BYTES")
+	})
+
+	if got := Bytes("TEST_BYTES", Kibibyte); got != 10*Megabyte {
+		t.Errorf("Bytes() = %v, want %v", got, 10*Megabyte)
+	}
+	if got := Bytes("TEST_INVALID_BYTES", Kibibyte); got != Kibibyte {
+		t.Errorf("Bytes() = %v, want %v", got, Kibibyte)
+	}
+	if got := Bytes("TEST_UNSET_BYTES", Kibibyte); got != Kibibyte {
+		t.Errorf("Bytes() = %v, want %v", got, Kibibyte)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anvidev/goenv"
+)
+
+type checkProblem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (c *cli) check(args []string) int {
+	fs := c.flagSet("check")
+	format := fs.String("format", "text", "output `format`: text or json")
+	strict := fs.Bool("strict", false, "exit non-zero on warnings too")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(c.stderr, "goenv check: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{".env"}
+	}
+
+	var problems []goenv.Problem
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			problems = append(problems, goenv.Problem{
+				Pos:      goenv.Position{Filename: file},
+				Severity: goenv.SeverityError,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		problems = append(problems, goenv.Lint(file, src)...)
+	}
+
+	failed := false
+	for _, p := range problems {
+		if p.Severity == goenv.SeverityError || *strict {
+			failed = true
+		}
+	}
+
+	if *format == "json" {
+		out := make([]checkProblem, len(problems))
+		for i, p := range problems {
+			out[i] = checkProblem{
+				File:     p.Pos.Filename,
+				Line:     p.Pos.Line,
+				Column:   p.Pos.Column,
+				Severity: p.Severity.String(),
+				Message:  p.Message,
+			}
+		}
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return c.fail(err)
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Fprintf(c.stdout, "%s: %s: %s\n", p.Pos, p.Severity, p.Message)
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
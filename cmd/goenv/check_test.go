@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env":      "A=1\nB=\"two\"\n",
+		"warn.env":  "A=1 \n",
+		"error.env": "A=1\nA=2\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		expected string
+	}{
+		{name: "Default file", code: 0, expected: ""},
+		{name: "Warnings only", args: []string{"warn.env"}, code: 0, expected: "warn.env:1:4: warning: trailing whitespace\n"},
+		{name: "Strict", args: []string{"--strict", "warn.env"}, code: 1, expected: "warn.env:1:4: warning: trailing whitespace\n"},
+		{name: "Errors", args: []string{".env", "error.env"}, code: 1, expected: "error.env:2:1: error: duplicate key A, first defined on line 1\n"},
+		{name: "Missing file", args: []string{"missing.env"}, code: 1, expected: "missing.env: error: open missing.env: no such file or directory\n"},
+		{name: "Unknown format", args: []string{"--format=xml"}, code: exitUsage, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, dir, "", append([]string{"check"}, tt.args...)...)
+			if code != tt.code {
+				t.Errorf("check = got exit code %d, expected %d (stderr %q)", code, tt.code, stderr)
+			}
+			if stdout != tt.expected {
+				t.Errorf("check = got output %q, expected %q", stdout, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheck_JSON(t *testing.T) {
+	dir := writeFiles(t, map[string]string{".env": "A=1\nA=2\n"})
+
+	code, stdout, _ := runCLI(t, dir, "", "check", "--format=json")
+	if code != 1 {
+		t.Errorf("check = got exit code %d, expected 1", code)
+	}
+
+	var got []checkProblem
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("check = invalid JSON %q: %v", stdout, err)
+	}
+	expected := checkProblem{File: ".env", Line: 2, Column: 1, Severity: "error", Message: "duplicate key A, first defined on line 1"}
+	if len(got) != 1 || got[0] != expected {
+		t.Errorf("check = got %+v, expected [%+v]", got, expected)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anvidev/goenv"
+)
+
+func (c *cli) encrypt(args []string) int {
+	fs := c.flagSet("encrypt")
+	values := fs.Bool("values", false, "only encrypt the values, keeping keys and comments readable")
+	output := fs.String("o", "", "write to `file`, or - for stdout (default file.enc, or the file itself with --values)")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	name := ".env"
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+	}
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return c.fail(err)
+	}
+	if goenv.IsEncrypted(string(src)) {
+		return c.fail(fmt.Errorf("%s is already encrypted", name))
+	}
+
+	cipher, err := goenv.CipherFromEnv()
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var out []byte
+	if *values {
+		out, err = cipher.EncryptValues(src)
+	} else {
+		var encrypted string
+		encrypted, err = cipher.Encrypt(src)
+		out = []byte(encrypted + "\n")
+	}
+	if err != nil {
+		return c.fail(fmt.Errorf("%s: %w", name, err))
+	}
+
+	dest := *output
+	if dest == "" {
+		dest = name + ".enc"
+		if *values {
+			dest = name
+		}
+	}
+	return c.writeOutput(dest, out)
+}
+
+func (c *cli) decrypt(args []string) int {
+	fs := c.flagSet("decrypt")
+	output := fs.String("o", "-", "write to `file` instead of stdout")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	name := ".env.enc"
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+	}
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	cipher, err := goenv.CipherFromEnv()
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var out []byte
+	if goenv.IsEncrypted(string(src)) {
+		out, err = cipher.Decrypt(string(src))
+	} else {
+		out, err = cipher.DecryptValues(src)
+	}
+	if err != nil {
+		return c.fail(fmt.Errorf("%s: %w", name, err))
+	}
+	return c.writeOutput(*output, out)
+}
+
+func (c *cli) keygen(args []string) int {
+	fs := c.flagSet("keygen")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	key, err := goenv.GenerateKey()
+	if err != nil {
+		return c.fail(err)
+	}
+	fmt.Fprintln(c.stdout, key)
+	return 0
+}
+
+// writeOutput writes data to stdout if dest is "-", or else to the file dest, readable only by its owner.
+func (c *cli) writeOutput(dest string, data []byte) int {
+	if dest == "-" {
+		c.stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(dest, data, 0o600); err != nil {
+		return c.fail(err)
+	}
+	return 0
+}
+
+// encryptionHelp describes where encrypt and decrypt take their key from.
+var encryptionHelp = strings.TrimSpace(`
+The key is read from GOENV_KEY (64 hex digits or base64), or derived from the
+password in GOENV_PASSWORD. GOENV_KEY_FILE and GOENV_PASSWORD_FILE may name a
+file to read them from. Create a key with "goenv keygen".`)
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestEncrypt(t *testing.T) {
+	t.Setenv("GOENV_KEY", testKey)
+	dir := writeFiles(t, map[string]string{".env": "# db\nDB_PASS=s3cret\n"})
+
+	code, _, stderr := runCLI(t, dir, "", "encrypt")
+	if code != 0 {
+		t.Fatalf("encrypt = got exit code %d (stderr %q)", code, stderr)
+	}
+	encrypted, err := os.ReadFile(filepath.Join(dir, ".env.enc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(encrypted), "enc:v1:key:") || strings.Contains(string(encrypted), "s3cret") {
+		t.Errorf("encrypt = got %q, expected an encrypted file", encrypted)
+	}
+
+	code, stdout, _ := runCLI(t, dir, "", "decrypt")
+	if code != 0 || stdout != "# db\nDB_PASS=s3cret\n" {
+		t.Errorf("decrypt = got %d, %q, expected the original file", code, stdout)
+	}
+
+	if runtime.GOOS != "windows" {
+		code, stdout, _ = runCLI(t, dir, "", "run", "-f", ".env.enc", "--", "sh", "-c", `printf %s "$DB_PASS"`)
+		if code != 0 || stdout != "s3cret" {
+			t.Errorf("run = got %d, %q, expected the decrypted value", code, stdout)
+		}
+	}
+
+	code, _, _ = runCLI(t, dir, "", "encrypt", ".env.enc")
+	if code != 1 {
+		t.Errorf("encrypt = got exit code %d for an encrypted file, expected 1", code)
+	}
+}
+
+func TestEncrypt_Values(t *testing.T) {
+	t.Setenv("GOENV_KEY", testKey)
+	dir := writeFiles(t, map[string]string{".env": "# db\nDB_HOST=localhost\nDB_PASS=s3cret\n"})
+
+	code, _, stderr := runCLI(t, dir, "", "encrypt", "--values")
+	if code != 0 {
+		t.Fatalf("encrypt = got exit code %d (stderr %q)", code, stderr)
+	}
+	encrypted, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	lines := strings.Split(string(encrypted), "\n")
+	if lines[0] != "# db" || !strings.HasPrefix(lines[1], "DB_HOST=enc:v1:key:") || !strings.HasPrefix(lines[2], "DB_PASS=enc:v1:key:") {
+		t.Errorf("encrypt = got %q, expected encrypted values", encrypted)
+	}
+
+	code, stdout, _ := runCLI(t, dir, "", "decrypt", ".env")
+	if code != 0 || stdout != "# db\nDB_HOST=localhost\nDB_PASS=s3cret\n" {
+		t.Errorf("decrypt = got %d, %q, expected the original file", code, stdout)
+	}
+
+	t.Setenv("GOENV_KEY", strings.Repeat("0", 64))
+	code, _, stderr = runCLI(t, dir, "", "decrypt", ".env")
+	if code != 1 || !strings.Contains(stderr, "DB_HOST: decryption failed") {
+		t.Errorf("decrypt = got %d, %q, expected decryption to fail with another key", code, stderr)
+	}
+}
+
+func TestEncrypt_NoKey(t *testing.T) {
+	for _, env := range []string{"GOENV_KEY", "GOENV_KEY_FILE", "GOENV_PASSWORD", "GOENV_PASSWORD_FILE"} {
+		t.Setenv(env, "")
+	}
+	dir := writeFiles(t, map[string]string{".env": "A=1\n"})
+
+	code, _, stderr := runCLI(t, dir, "", "encrypt")
+	if code != 1 || !strings.Contains(stderr, "no decryption key") {
+		t.Errorf("encrypt = got %d, %q, expected a missing key error", code, stderr)
+	}
+}
+
+func TestKeygen(t *testing.T) {
+	code, stdout, _ := runCLI(t, "", "", "keygen")
+	if code != 0 || len(strings.TrimSpace(stdout)) != 64 {
+		t.Errorf("keygen = got %d, %q, expected 64 hex digits", code, stdout)
+	}
+}
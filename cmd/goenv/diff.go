@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anvidev/goenv"
+)
+
+func (c *cli) diff(args []string) int {
+	fs := c.flagSet("diff")
+	showValues := fs.Bool("show-values", false, "print values instead of masking them")
+	keysOnly := fs.Bool("keys-only", false, "only compare which keys are set, not their values")
+	environ := fs.Bool("environ", false, "compare the file against the keys it sets in the current environment")
+	format := fs.String("format", "text", "output `format`: text or json")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(c.stderr, "goenv diff: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	files := fs.Args()
+	if (*environ && len(files) != 1) || (!*environ && len(files) != 2) {
+		fs.Usage()
+		return exitUsage
+	}
+
+	oldVars, err := goenv.Read(files[0])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var newVars map[string]string
+	if *environ {
+		newVars = make(map[string]string)
+		for key := range oldVars {
+			if value, ok := os.LookupEnv(key); ok {
+				newVars[key] = value
+			}
+		}
+	} else if newVars, err = goenv.Read(files[1]); err != nil {
+		return c.fail(err)
+	}
+
+	changes := goenv.Diff(oldVars, newVars)
+	if *keysOnly {
+		changes = goenv.ChangeSet{Added: keysOf(changes.Added), Removed: keysOf(changes.Removed), Changed: []goenv.Change{}}
+	} else if !*showValues {
+		changes = changes.Masked()
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(changes); err != nil {
+			return c.fail(err)
+		}
+	} else {
+		c.printChanges(changes, *keysOnly)
+	}
+
+	if changes.Empty() {
+		return 0
+	}
+	return 1
+}
+
+// keysOf returns the changes without their values.
+func keysOf(changes []goenv.Change) []goenv.Change {
+	keys := make([]goenv.Change, len(changes))
+	for i, ch := range changes {
+		keys[i] = goenv.Change{Key: ch.Key}
+	}
+	return keys
+}
+
+func (c *cli) printChanges(changes goenv.ChangeSet, keysOnly bool) {
+	for _, ch := range changes.Added {
+		if keysOnly {
+			fmt.Fprintf(c.stdout, "+ %s\n", ch.Key)
+		} else {
+			fmt.Fprintf(c.stdout, "+ %s=%s\n", ch.Key, ch.New)
+		}
+	}
+	for _, ch := range changes.Removed {
+		if keysOnly {
+			fmt.Fprintf(c.stdout, "- %s\n", ch.Key)
+		} else {
+			fmt.Fprintf(c.stdout, "- %s=%s\n", ch.Key, ch.Old)
+		}
+	}
+	for _, ch := range changes.Changed {
+		fmt.Fprintf(c.stdout, "~ %s: %s -> %s\n", ch.Key, ch.Old, ch.New)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/anvidev/goenv"
+)
+
+func TestDiff(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"staging.env":    "A=1\nB=2\nC=3\n",
+		"production.env": "A=1\nB=20\nD=4\n",
+		"same.env":       "A=1\nB=2\nC=3\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		expected string
+	}{
+		{
+			name:     "Masked",
+			args:     []string{"staging.env", "production.env"},
+			code:     1,
+			expected: "+ D=***\n- C=***\n~ B: *** -> ***\n",
+		},
+		{
+			name:     "Show values",
+			args:     []string{"--show-values", "staging.env", "production.env"},
+			code:     1,
+			expected: "+ D=4\n- C=3\n~ B: 2 -> 20\n",
+		},
+		{
+			name:     "Keys only",
+			args:     []string{"--keys-only", "staging.env", "production.env"},
+			code:     1,
+			expected: "+ D\n- C\n",
+		},
+		{
+			name: "No differences",
+			args: []string{"staging.env", "same.env"},
+			code: 0,
+		},
+		{
+			name: "Missing file",
+			args: []string{"staging.env", "missing.env"},
+			code: 1,
+		},
+		{
+			name: "Missing argument",
+			args: []string{"staging.env"},
+			code: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, dir, "", append([]string{"diff"}, tt.args...)...)
+			if code != tt.code {
+				t.Errorf("diff = got exit code %d, expected %d (stderr %q)", code, tt.code, stderr)
+			}
+			if stdout != tt.expected {
+				t.Errorf("diff = got output %q, expected %q", stdout, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiff_Environ(t *testing.T) {
+	dir := writeFiles(t, map[string]string{".env": "GOENV_DIFF_A=file\nGOENV_DIFF_B=file\nGOENV_DIFF_C=same\n"})
+	t.Setenv("GOENV_DIFF_A", "env")
+	t.Setenv("GOENV_DIFF_C", "same")
+
+	code, stdout, _ := runCLI(t, dir, "", "diff", "--environ", "--show-values", "--format=json", ".env")
+	if code != 1 {
+		t.Errorf("diff = got exit code %d, expected 1", code)
+	}
+
+	var got goenv.ChangeSet
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("diff = invalid JSON %q: %v", stdout, err)
+	}
+	expected := goenv.ChangeSet{
+		Added:   []goenv.Change{},
+		Removed: []goenv.Change{{Key: "GOENV_DIFF_B", Old: "file"}},
+		Changed: []goenv.Change{{Key: "GOENV_DIFF_A", Old: "file", New: "env"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("diff = got %+v, expected %+v", got, expected)
+	}
+}
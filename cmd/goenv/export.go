@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anvidev/goenv"
+)
+
+// exporters write variables in the syntax of a shell or tool, one variable at a time.
+var exporters = map[string]func(w io.Writer, key, value string) error{
+	"sh":         exportPOSIX,
+	"bash":       exportPOSIX,
+	"fish":       exportFish,
+	"powershell": exportPowerShell,
+	"yaml":       exportYAML,
+	"docker":     exportDocker,
+	"systemd":    exportSystemd,
+	"github":     exportGitHub,
+}
+
+func (c *cli) export(args []string) int {
+	fs := c.flagSet("export")
+	format := fs.String("format", "sh", "output `format`: sh, bash, fish, powershell, json, yaml, docker, systemd or github")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	exporter, ok := exporters[*format]
+	if !ok && *format != "json" {
+		fmt.Fprintf(c.stderr, "goenv export: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	vars, err := goenv.Read(fs.Args()...)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(vars); err != nil {
+			return c.fail(err)
+		}
+		return 0
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// nothing is written if any variable cannot be exported
+	var buf bytes.Buffer
+	for _, key := range keys {
+		if err := exporter(&buf, key, vars[key]); err != nil {
+			return c.fail(fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	if _, err := buf.WriteTo(c.stdout); err != nil {
+		return c.fail(err)
+	}
+	return 0
+}
+
+var errInvalidName = fmt.Errorf("not a valid shell variable name")
+
+// exportPOSIX writes export KEY='value', which keeps every character of the value literal.
+func exportPOSIX(w io.Writer, key, value string) error {
+	if !goenv.IsShellName(key) {
+		return errInvalidName
+	}
+	_, err := fmt.Fprintf(w, "export %s='%s'\n", key, strings.ReplaceAll(value, "'", `'\''`))
+	return err
+}
+
+// exportFish writes set -gx KEY 'value'. Fish single quotes only interpret \\ and \'.
+func exportFish(w io.Writer, key, value string) error {
+	if !goenv.IsShellName(key) {
+		return errInvalidName
+	}
+	value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	_, err := fmt.Fprintf(w, "set -gx %s '%s'\n", key, value)
+	return err
+}
+
+// exportPowerShell writes $env:KEY = 'value'. Inside PowerShell single quotes only a doubled quote is special.
+func exportPowerShell(w io.Writer, key, value string) error {
+	name := "$env:" + key
+	if !goenv.IsShellName(key) {
+		name = "${env:" + strings.ReplaceAll(key, "}", "`}") + "}"
+	}
+	_, err := fmt.Fprintf(w, "%s = '%s'\n", name, strings.ReplaceAll(value, "'", "''"))
+	return err
+}
+
+// exportYAML writes KEY: "value" with YAML double quoted escapes.
+func exportYAML(w io.Writer, key, value string) error {
+	if !goenv.IsShellName(key) {
+		key = strconv.Quote(key)
+	}
+	_, err := fmt.Fprintf(w, "%s: %s\n", key, strconv.Quote(value))
+	return err
+}
+
+// exportDocker writes KEY=value for docker --env-file, which takes every line literally
+// and has no way to express a line break.
+func exportDocker(w io.Writer, key, value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("docker env files cannot hold values with line breaks")
+	}
+	_, err := fmt.Fprintf(w, "%s=%s\n", key, value)
+	return err
+}
+
+// exportSystemd writes an Environment="KEY=value" directive for a unit file.
+func exportSystemd(w io.Writer, key, value string) error {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "%", "%%")
+	_, err := fmt.Fprintf(w, "Environment=\"%s=%s\"\n", r.Replace(key), r.Replace(value))
+	return err
+}
+
+// exportGitHub writes a line for the $GITHUB_ENV file of GitHub Actions. Values with line breaks
+// use the heredoc syntax with a random delimiter that cannot occur in the value.
+func exportGitHub(w io.Writer, key, value string) error {
+	if !strings.ContainsAny(value, "\r\n") {
+		_, err := fmt.Fprintf(w, "%s=%s\n", key, value)
+		return err
+	}
+
+	var delimiter string
+	for delimiter == "" || strings.Contains(value, delimiter) {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		delimiter = "ghadelimiter_" + hex.EncodeToString(b)
+	}
+	_, err := fmt.Fprintf(w, "%s<<%s\n%s\n%s\n", key, delimiter, value, delimiter)
+	return err
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env":      "A=\"it's 50%\"\nB=\"x\ny\"\nC=a\\b\n",
+		"plain.env": "A=1\n",
+		"name.env":  "app.name=x\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		expected string
+	}{
+		{
+			name:     "sh",
+			expected: "export A='it'\\''s 50%'\nexport B='x\ny'\nexport C='a\\b'\n",
+		},
+		{
+			name:     "fish",
+			args:     []string{"--format=fish"},
+			expected: "set -gx A 'it\\'s 50%'\nset -gx B 'x\ny'\nset -gx C 'a\\\\b'\n",
+		},
+		{
+			name:     "powershell",
+			args:     []string{"--format=powershell"},
+			expected: "$env:A = 'it''s 50%'\n$env:B = 'x\ny'\n$env:C = 'a\\b'\n",
+		},
+		{
+			name:     "json",
+			args:     []string{"--format=json"},
+			expected: "{\n  \"A\": \"it's 50%\",\n  \"B\": \"x\\ny\",\n  \"C\": \"a\\\\b\"\n}\n",
+		},
+		{
+			name:     "yaml",
+			args:     []string{"--format=yaml"},
+			expected: "A: \"it's 50%\"\nB: \"x\\ny\"\nC: \"a\\\\b\"\n",
+		},
+		{
+			name:     "docker",
+			args:     []string{"--format=docker", "plain.env"},
+			expected: "A=1\n",
+		},
+		{
+			name: "docker with line break",
+			args: []string{"--format=docker"},
+			code: 1,
+		},
+		{
+			name:     "systemd",
+			args:     []string{"--format=systemd"},
+			expected: "Environment=\"A=it's 50%%\"\nEnvironment=\"B=x\\ny\"\nEnvironment=\"C=a\\\\b\"\n",
+		},
+		{
+			name:     "powershell invalid name",
+			args:     []string{"--format=powershell", "name.env"},
+			expected: "${env:app.name} = 'x'\n",
+		},
+		{
+			name: "sh invalid name",
+			args: []string{"name.env"},
+			code: 1,
+		},
+		{
+			name: "Unknown format",
+			args: []string{"--format=toml"},
+			code: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, dir, "", append([]string{"export"}, tt.args...)...)
+			if code != tt.code {
+				t.Errorf("export = got exit code %d, expected %d (stderr %q)", code, tt.code, stderr)
+			}
+			if stdout != tt.expected {
+				t.Errorf("export = got output %q, expected %q", stdout, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExport_GitHub(t *testing.T) {
+	dir := writeFiles(t, map[string]string{".env": "A=1\nB=\"x\ny\"\n"})
+
+	_, stdout, _ := runCLI(t, dir, "", "export", "--format=github")
+	re := regexp.MustCompile(`^A=1\nB<<(ghadelimiter_[0-9a-f]{32})\nx\ny\n(ghadelimiter_[0-9a-f]{32})\n$`)
+	m := re.FindStringSubmatch(stdout)
+	if m == nil || m[1] != m[2] {
+		t.Errorf("export = got output %q, expected a heredoc for B", stdout)
+	}
+}
+
+func TestExport_Shell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	dir := writeFiles(t, map[string]string{".env": "A=\"it's $HOME `x` \\n\"\nB=\"x\ny\"\n"})
+
+	_, stdout, _ := runCLI(t, dir, "", "export")
+	out, err := exec.Command(sh, "-c", stdout+`printf '%s|%s' "$A" "$B"`).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "it's $HOME `x` \\n|x\ny"; string(out) != expected {
+		t.Errorf("export = sh read %q, expected %q", out, expected)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/anvidev/goenv"
+)
+
+func (c *cli) fmt(args []string) int {
+	fs := c.flagSet("fmt")
+	write := fs.Bool("w", false, "write the result to the file instead of stdout")
+	diff := fs.Bool("d", false, "print a diff instead of the formatted file")
+	list := fs.Bool("l", false, "list files whose formatting differs")
+	sortKeys := fs.Bool("sort", false, "sort the keys within blocks separated by blank lines or comments")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		if *write {
+			fmt.Fprintln(c.stderr, "goenv fmt: cannot use -w with standard input")
+			return exitUsage
+		}
+		src, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return c.fail(err)
+		}
+		if err := c.formatFile("<standard input>", src, *sortKeys, false, *diff, *list); err != nil {
+			return c.fail(err)
+		}
+		return 0
+	}
+
+	code := 0
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err == nil {
+			err = c.formatFile(file, src, *sortKeys, *write, *diff, *list)
+		}
+		if err != nil {
+			code = c.fail(err)
+		}
+	}
+	return code
+}
+
+func (c *cli) formatFile(name string, src []byte, sortKeys, write, diff, list bool) error {
+	res, err := goenv.Format(src, sortKeys)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	changed := !bytes.Equal(src, res)
+
+	if list && changed {
+		fmt.Fprintln(c.stdout, name)
+	}
+	if write && changed {
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, res, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if diff && changed {
+		fmt.Fprint(c.stdout, unifiedDiff(name+".orig", name, string(src), string(res)))
+	}
+	if !list && !write && !diff {
+		c.stdout.Write(res)
+	}
+	return nil
+}
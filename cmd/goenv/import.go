@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anvidev/goenv"
+)
+
+func (c *cli) importCmd(args []string) int {
+	fs := c.flagSet("import")
+	from := fs.String("from", "", "input `format`: env, json, yaml or compose (default from the file name)")
+	separator := fs.String("separator", "_", "`separator` joining the keys of nested JSON and YAML values")
+	service := fs.String("service", "", "compose `service` to read the environment of")
+	upper := fs.Bool("upper", false, "convert keys to upper case")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	name := "<standard input>"
+	var src []byte
+	var err error
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+		src, err = os.ReadFile(name)
+	} else {
+		src, err = io.ReadAll(c.stdin)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+
+	format := *from
+	if format == "" {
+		if fs.NArg() == 0 {
+			fmt.Fprintln(c.stderr, "goenv import: use --from to set the format of standard input")
+			return exitUsage
+		}
+		format = detectFormat(name)
+	}
+
+	var entries []goenv.Entry
+	switch format {
+	case "env":
+		entries, err = goenv.ParseEnv(name, src)
+	case "json":
+		entries, err = goenv.ParseJSON(name, src, *separator)
+	case "yaml":
+		entries, err = goenv.ParseYAML(name, src, *separator)
+	case "compose":
+		entries, err = goenv.ParseCompose(name, src, *service)
+	default:
+		fmt.Fprintf(c.stderr, "goenv import: unknown format %q\n", format)
+		return exitUsage
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+
+	if *upper {
+		for i := range entries {
+			entries[i].Key = strings.ToUpper(entries[i].Key)
+		}
+	}
+	if err := goenv.Write(c.stdout, goenv.EntryMap(entries)); err != nil {
+		return c.fail(err)
+	}
+	return 0
+}
+
+// detectFormat guesses the format of a file from its name.
+func detectFormat(name string) string {
+	base := strings.ToLower(filepath.Base(name))
+	switch ext := filepath.Ext(base); {
+	case ext == ".json":
+		return "json"
+	case (ext == ".yml" || ext == ".yaml") && strings.Contains(base, "compose"):
+		return "compose"
+	case ext == ".yml" || ext == ".yaml":
+		return "yaml"
+	}
+	return "env"
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestImport(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.json":        `{"db": {"host": "x", "port": 5432}, "name": "two words"}`,
+		"config.yaml":        "db:\n  host: x\nname: ' padded'\n",
+		"docker-compose.yml": "services:\n  web:\n    environment:\n      - A=1\n  worker:\n    environment:\n      B: 2\n",
+		".env":               "B=2\nA=1\n",
+		"invalid.json":       `["x"]`,
+		"quote.json":         `{"A": " \"x\""}`,
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		code     int
+		expected string
+	}{
+		{name: "JSON", args: []string{"config.json"}, expected: "db_host=x\ndb_port=5432\nname=two words\n"},
+		{name: "JSON separator and upper", args: []string{"--separator=__", "--upper", "config.json"}, expected: "DB__HOST=x\nDB__PORT=5432\nNAME=two words\n"},
+		{name: "YAML", args: []string{"config.yaml"}, expected: "db_host=x\nname=\" padded\"\n"},
+		{name: "Compose", args: []string{"--service=worker", "docker-compose.yml"}, expected: "B=2\n"},
+		{name: "Compose without service", args: []string{"docker-compose.yml"}, code: 1},
+		{name: "Dotenv", args: []string{".env"}, expected: "A=1\nB=2\n"},
+		{name: "Stdin", args: []string{"--from=yaml"}, stdin: "A: 1\n", expected: "A=1\n"},
+		{name: "Stdin without format", code: exitUsage},
+		{name: "Invalid file", args: []string{"invalid.json"}, code: 1},
+		{name: "Unwritable value", args: []string{"quote.json"}, code: 1},
+		{name: "Unknown format", args: []string{"--from=toml", "config.json"}, code: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, dir, tt.stdin, append([]string{"import"}, tt.args...)...)
+			if code != tt.code {
+				t.Errorf("import = got exit code %d, expected %d (stderr %q)", code, tt.code, stderr)
+			}
+			if stdout != tt.expected {
+				t.Errorf("import = got output %q, expected %q", stdout, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"config.json":           "json",
+		"config.YAML":           "yaml",
+		"app.yml":               "yaml",
+		"docker-compose.yml":    "compose",
+		"compose.override.yaml": "compose",
+		".env.production":       "env",
+	}
+	for name, expected := range tests {
+		if got := detectFormat(name); got != expected {
+			t.Errorf("detectFormat(%q) = got %q, expected %q", name, got, expected)
+		}
+	}
+}
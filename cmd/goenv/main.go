@@ -0,0 +1,192 @@
+// Command goenv loads dotenv files with the same rules as the goenv package,
+// so tooling outside of Go shares one dotenv implementation.
+//
+// Usage:
+//
+//	goenv <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	check    report problems in dotenv files
+//	decrypt  decrypt an encrypted dotenv file or its encrypted values
+//	diff     show the variables added, removed or changed between dotenv files
+//	encrypt  encrypt a dotenv file, or only its values
+//	export   print the variables of dotenv files for a shell or tool
+//	fmt      rewrite dotenv files in canonical form
+//	import   convert JSON, YAML and docker compose files to dotenv
+//	keygen   print a new random key for GOENV_KEY
+//	run      run a command with variables loaded from dotenv files
+//
+// Run "goenv <command> -h" for the flags of a command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// exitUsage is the exit code for invalid invocations.
+const exitUsage = 2
+
+type command struct {
+	usage string
+	short string
+	// help is printed after the flags, if set.
+	help string
+	run  func(c *cli, args []string) int
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"check": {
+			usage: "check [--format text|json] [--strict] [file...]",
+			short: "report problems in dotenv files",
+			run:   (*cli).check,
+		},
+		"decrypt": {
+			usage: "decrypt [-o file] [file]",
+			short: "decrypt an encrypted dotenv file or its encrypted values",
+			help:  encryptionHelp,
+			run:   (*cli).decrypt,
+		},
+		"diff": {
+			usage: "diff [--show-values] [--keys-only] [--format text|json] old-file new-file\n       goenv diff --environ [flags] file",
+			short: "show the variables added, removed or changed between dotenv files",
+			run:   (*cli).diff,
+		},
+		"encrypt": {
+			usage: "encrypt [--values] [-o file] [file]",
+			short: "encrypt a dotenv file, or only its values",
+			help:  encryptionHelp,
+			run:   (*cli).encrypt,
+		},
+		"export": {
+			usage: "export [--format sh|bash|fish|powershell|json|yaml|docker|systemd|github] [file...]",
+			short: "print the variables of dotenv files for a shell or tool",
+			run:   (*cli).export,
+		},
+		"fmt": {
+			usage: "fmt [-w] [-d] [-l] [--sort] [file...]",
+			short: "rewrite dotenv files in canonical form",
+			run:   (*cli).fmt,
+		},
+		"import": {
+			usage: "import [--from env|json|yaml|compose] [--separator sep] [--service name] [--upper] [file]",
+			short: "convert JSON, YAML and docker compose files to dotenv",
+			run:   (*cli).importCmd,
+		},
+		"keygen": {
+			usage: "keygen",
+			short: "print a new random key for GOENV_KEY",
+			run:   (*cli).keygen,
+		},
+		"run": {
+			usage: "run [-f file]... [--override] [--cascade env] [--clean] -- command [args...]",
+			short: "run a command with variables loaded from dotenv files",
+			run:   (*cli).run,
+		},
+	}
+}
+
+// cli holds the streams a command reads from and writes to.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(c.main(os.Args[1:]))
+}
+
+func (c *cli) main(args []string) int {
+	if len(args) == 0 {
+		c.usage()
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == "-h" || name == "--help" || name == "help" {
+		c.usage()
+		return 0
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(c.stderr, "goenv: unknown command %q\n", name)
+		c.usage()
+		return exitUsage
+	}
+	return cmd.run(c, args[1:])
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "Usage: goenv <command> [flags] [arguments]")
+	fmt.Fprintln(c.stderr)
+	fmt.Fprintln(c.stderr, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.stderr, "  %-8s %s\n", name, commands[name].short)
+	}
+}
+
+// flagSet returns a flag set for the named command that reports errors to stderr.
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: goenv %s\n", commands[name].usage)
+		fs.PrintDefaults()
+		if help := commands[name].help; help != "" {
+			fmt.Fprintf(c.stderr, "\n%s\n", help)
+		}
+	}
+	return fs
+}
+
+// parseFlags parses args with fs and returns the exit code to stop with, if any.
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0, true
+	}
+	if err != nil {
+		return exitUsage, true
+	}
+	return 0, false
+}
+
+// fail reports err and returns the exit code for it. Errors from the goenv package already carry its prefix.
+func (c *cli) fail(err error) int {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "goenv") {
+		msg = "goenv: " + msg
+	}
+	fmt.Fprintln(c.stderr, msg)
+	return 1
+}
+
+// fileList is a repeatable flag collecting file names.
+type fileList []string
+
+func (f *fileList) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
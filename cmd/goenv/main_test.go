@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCLI runs the command line args in dir and returns the exit code and output streams.
+func runCLI(t *testing.T, dir string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	if dir != "" {
+		chdir(t, dir)
+	}
+	var stdout, stderr bytes.Buffer
+	c := &cli{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}
+	code := c.main(args)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFiles writes every file of files to a new temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMain_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{name: "No command", args: nil, code: exitUsage},
+		{name: "Help", args: []string{"help"}, code: 0},
+		{name: "Unknown command", args: []string{"nope"}, code: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, "", "", tt.args...)
+			if code != tt.code {
+				t.Errorf("main() = got exit code %d, expected %d", code, tt.code)
+			}
+			if !strings.Contains(stderr, "Usage: goenv") {
+				t.Errorf("main() = got stderr %q, expected usage", stderr)
+			}
+		})
+	}
+}
+
+// chdir changes the working directory to dir for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
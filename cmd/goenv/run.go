@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/anvidev/goenv"
+)
+
+func (c *cli) run(args []string) int {
+	fs := c.flagSet("run")
+	var files fileList
+	fs.Var(&files, "f", "dotenv `file` to load, may be repeated (default .env)")
+	override := fs.Bool("override", false, "let every file override the environment, not only the first")
+	cascade := fs.String("cascade", "", "also load .env.`env`.local, .env.local, .env.env and .env if they exist, only for unset variables unless --override is set")
+	clean := fs.Bool("clean", false, "start from an empty environment instead of the current one")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	argv := fs.Args()
+	if len(argv) == 0 {
+		fmt.Fprintln(c.stderr, "goenv run: missing command")
+		fs.Usage()
+		return exitUsage
+	}
+
+	// without --override the cascade only fills in unset variables, whichever of its files exist
+	filenames := []string(files)
+	var fill []string
+	if *cascade != "" {
+		fill = cascadeFiles(*cascade)
+		if *override {
+			filenames, fill = append(filenames, fill...), nil
+		}
+	}
+
+	env := map[string]string{}
+	if !*clean {
+		env = environMap(os.Environ())
+	}
+	if len(filenames) > 0 || *cascade == "" {
+		var err error
+		if env, err = goenv.Merge(env, *override, filenames...); err != nil {
+			return c.fail(err)
+		}
+	}
+	if len(fill) > 0 {
+		vars, err := goenv.Read(fill...)
+		if err != nil {
+			return c.fail(err)
+		}
+		for key, value := range vars {
+			if _, ok := env[key]; !ok {
+				env[key] = value
+			}
+		}
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = goenv.Environ(env)
+	cmd.Stdin = c.stdin
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+	return c.exec(cmd)
+}
+
+// cascadeFiles returns the existing files of the cascade for env, from the highest precedence to the lowest.
+func cascadeFiles(env string) []string {
+	candidates := []string{".env." + env + ".local", ".env.local", ".env." + env, ".env"}
+	var files []string
+	for _, name := range candidates {
+		if info, err := os.Stat(name); err == nil && info.Mode().IsRegular() {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// environMap converts entries of the form "KEY=value" to a map.
+func environMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			continue
+		}
+		env[key] = value
+	}
+	return env
+}
+
+// exec starts cmd, forwards signals to it while it runs and returns its exit code.
+func (c *cli) exec(cmd *exec.Cmd) int {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		c.fail(err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return 127
+		}
+		return 126
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	close(done)
+	return c.exitCode(err)
+}
+
+// exitCode returns the exit code of a finished command, or 128 plus the signal number if a signal killed it.
+func (c *cli) exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return c.fail(err)
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return exitErr.ExitCode()
+}
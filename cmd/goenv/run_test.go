@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	t.Setenv("GOENV_RUN_SHELL", "shell")
+	t.Setenv("APP_ENV", "shell")
+	t.Setenv("LOCAL", "shell")
+
+	dir := writeFiles(t, map[string]string{
+		".env":                  "APP_ENV=base\nBASE=base\nLOCAL=base\n",
+		".env.local":            "LOCAL=local\n",
+		".env.production":       "APP_ENV=production\nBASE=production\n",
+		".env.production.local": "SECRET=local\n",
+		"extra.env":             "APP_ENV=extra\nEXTRA=extra\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		script   string
+		code     int
+		expected string
+	}{
+		{
+			name:     "Default file overrides environment",
+			script:   `echo "$APP_ENV $BASE $GOENV_RUN_SHELL"`,
+			expected: "base base shell\n",
+		},
+		{
+			name:     "Later files keep environment",
+			args:     []string{"-f", "extra.env", "-f", ".env"},
+			script:   `echo "$APP_ENV $EXTRA $BASE"`,
+			expected: "extra extra base\n",
+		},
+		{
+			name:     "Cascade",
+			args:     []string{"--cascade", "production"},
+			script:   `echo "$APP_ENV $BASE $LOCAL $SECRET"`,
+			expected: "shell production shell local\n",
+		},
+		{
+			name:     "Cascade keeps environment whichever file comes first",
+			args:     []string{"--cascade", "staging"},
+			script:   `echo "$APP_ENV $LOCAL $BASE"`,
+			expected: "shell shell base\n",
+		},
+		{
+			name:     "Cascade after file",
+			args:     []string{"-f", "extra.env", "--cascade", "production"},
+			script:   `echo "$APP_ENV $EXTRA $BASE $SECRET"`,
+			expected: "extra extra production local\n",
+		},
+		{
+			name:     "Cascade with override",
+			args:     []string{"--cascade", "production", "--override"},
+			script:   `echo "$APP_ENV $BASE $LOCAL $SECRET"`,
+			expected: "production production local local\n",
+		},
+		{
+			name:     "Clean environment",
+			args:     []string{"--clean", "-f", "extra.env"},
+			script:   `echo "$APP_ENV:$GOENV_RUN_SHELL"`,
+			expected: "extra:\n",
+		},
+		{
+			name:   "Exit code",
+			script: `exit 3`,
+			code:   3,
+		},
+		{
+			name:   "Killed by signal",
+			script: `kill -TERM $$`,
+			code:   143,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"run"}, tt.args...)
+			args = append(args, "--", "sh", "-c", tt.script)
+			// --clean also drops PATH, so the shell is given by its full path.
+			sh, _ := exec.LookPath("sh")
+			args[len(args)-3] = sh
+
+			code, stdout, stderr := runCLI(t, dir, "", args...)
+			if code != tt.code {
+				t.Errorf("run = got exit code %d, expected %d (stderr %q)", code, tt.code, stderr)
+			}
+			if stdout != tt.expected {
+				t.Errorf("run = got output %q, expected %q", stdout, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{".env": "A=1\n"})
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{name: "Missing command", args: []string{"run"}, code: exitUsage},
+		{name: "Unknown flag", args: []string{"run", "--nope", "--", "true"}, code: exitUsage},
+		{name: "Missing file", args: []string{"run", "-f", "missing.env", "--", "true"}, code: 1},
+		{name: "Command not found", args: []string{"run", "--", "goenv-no-such-command"}, code: 127},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, dir, "", tt.args...)
+			if code != tt.code {
+				t.Errorf("run = got exit code %d, expected %d (stderr %q)", code, tt.code, stderr)
+			}
+			if stderr == "" {
+				t.Errorf("run = expected an error on stderr")
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// forwardedSignals are relayed from goenv run to the child process.
+var forwardedSignals = []os.Signal{os.Interrupt}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			name:     "Equal",
+			a:        "A=1\nB=2\n",
+			b:        "A=1\nB=2\n",
+			expected: "",
+		},
+		{
+			name:     "Changed line",
+			a:        "A=1\nB=2\nC=3\n",
+			b:        "A=1\nB=3\nC=3\n",
+			expected: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n A=1\n-B=2\n+B=3\n C=3\n",
+		},
+		{
+			name:     "Added to empty",
+			a:        "",
+			b:        "A=1\n",
+			expected: "--- a\n+++ b\n@@ -0,0 +1 @@\n+A=1\n",
+		},
+		{
+			name:     "Separate hunks",
+			a:        "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:        "0\n2\n3\n4\n5\n6\n7\n8\n9\nX\n",
+			expected: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+X\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a", "b", tt.a, tt.b); got != tt.expected {
+				t.Errorf("unifiedDiff() = got:\n%s\nexpected:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
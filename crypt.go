@@ -0,0 +1,328 @@
+package goenv
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EncryptedPrefix starts every encrypted file and value.
+//
+// An encrypted value has the form
+//
+//	enc:v1:key:<payload>
+//	enc:v1:pbkdf2-sha256:<iterations>:<salt>:<payload>
+//
+// for a value encrypted with a key and with a password respectively. The payload is the 12 byte
+// nonce followed by the AES-256-GCM ciphertext, and it is base64 encoded without padding, like the
+// salt. Everything before the payload is authenticated as additional data. An encrypted file holds
+// the whole content of a dotenv file as a single encrypted value.
+const EncryptedPrefix = "enc:v1:"
+
+// ErrNoKey is returned when an encrypted file or value is read, but no key or password is configured.
+var ErrNoKey = errors.New("no decryption key, set GOENV_KEY, GOENV_KEY_FILE or GOENV_PASSWORD")
+
+// errDecrypt hides why decryption failed, as it tells an attacker nothing useful.
+var errDecrypt = errors.New("decryption failed: wrong key or corrupted data")
+
+const (
+	// KeyEnv holds a 32 byte key, hex or base64 encoded. KeyEnv+"_FILE" may hold the path of a file with the key.
+	KeyEnv = "GOENV_KEY"
+	// PasswordEnv holds a password to derive the key from. PasswordEnv+"_FILE" may hold the path of a file with it.
+	PasswordEnv = "GOENV_PASSWORD"
+
+	kdfKey    = "key"
+	kdfPBKDF2 = "pbkdf2-sha256"
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	pbkdf2Iterations = 600_000
+	// maxPBKDF2Iterations bounds the iterations read from an encrypted value, which is untrusted until
+	// it is authenticated, so a tampered value cannot make decryption run for hours.
+	maxPBKDF2Iterations = 4 * pbkdf2Iterations
+	saltSize            = 16
+)
+
+// Cipher encrypts and decrypts files and values with AES-256-GCM, using either a key or a key
+// derived from a password with PBKDF2-HMAC-SHA256. It is safe for concurrent use.
+type Cipher struct {
+	key        []byte
+	password   string
+	iterations int
+
+	mu   sync.Mutex
+	salt []byte
+	// derived caches the keys derived from the password, by iterations and salt.
+	derived map[string][]byte
+}
+
+// NewKeyCipher returns a Cipher that uses key, which must be 32 bytes long.
+func NewKeyCipher(key []byte) (*Cipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	return &Cipher{key: bytes.Clone(key)}, nil
+}
+
+// NewPasswordCipher returns a Cipher that derives its key from password with PBKDF2-HMAC-SHA256,
+// using 600000 iterations and a random 16 byte salt that is stored with the encrypted data.
+// The key is derived once per salt, so a Cipher encrypts all values with the same salt.
+func NewPasswordCipher(password string) (*Cipher, error) {
+	if password == "" {
+		return nil, errors.New("password must not be empty")
+	}
+	return &Cipher{password: password, iterations: pbkdf2Iterations}, nil
+}
+
+// CipherFromEnv returns a Cipher for the key in GOENV_KEY, or if that is unset, for the password
+// in GOENV_PASSWORD. Both may also be read from a file named by GOENV_KEY_FILE or GOENV_PASSWORD_FILE.
+// It returns ErrNoKey if none of them is set.
+func CipherFromEnv() (*Cipher, error) {
+	key, found, err := defaultOptions.lookupEnv(KeyEnv)
+	if err != nil {
+		return nil, err
+	}
+	if found && key != "" {
+		raw, err := ParseKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", KeyEnv, err.Error())
+		}
+		return NewKeyCipher(raw)
+	}
+
+	password, found, err := defaultOptions.lookupEnv(PasswordEnv)
+	if err != nil {
+		return nil, err
+	}
+	if found && password != "" {
+		return NewPasswordCipher(password)
+	}
+	return nil, ErrNoKey
+}
+
+// GenerateKey returns a new random key, hex encoded for GOENV_KEY.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// ParseKey decodes a 32 byte key from 64 hex digits or from base64.
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := hex.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil && len(key) == 32 {
+			return key, nil
+		}
+	}
+	return nil, errors.New("key must be 32 bytes, encoded as 64 hex digits or base64")
+}
+
+// IsEncrypted reports whether s is an encrypted file or value.
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(s, EncryptedPrefix)
+}
+
+// Encrypt encrypts plaintext and returns it in the form described by EncryptedPrefix.
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	header := EncryptedPrefix + kdfKey + ":"
+	key := c.key
+	if key == nil {
+		salt, err := c.passwordSalt()
+		if err != nil {
+			return "", err
+		}
+		header = EncryptedPrefix + kdfPBKDF2 + ":" + strconv.Itoa(c.iterations) + ":" + base64.RawStdEncoding.EncodeToString(salt) + ":"
+		key = c.deriveKey(salt, c.iterations)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(header))
+	return header + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a file or value returned by Encrypt.
+func (c *Cipher) Decrypt(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if !IsEncrypted(s) {
+		return nil, errors.New("not encrypted by goenv")
+	}
+
+	i := strings.LastIndexByte(s, ':')
+	header, payload := s[:i+1], s[i+1:]
+	params := strings.Split(strings.TrimSuffix(strings.TrimPrefix(header, EncryptedPrefix), ":"), ":")
+
+	var key []byte
+	switch {
+	case len(params) == 1 && params[0] == kdfKey:
+		if c.key == nil {
+			return nil, fmt.Errorf("value is encrypted with a key, set %s", KeyEnv)
+		}
+		key = c.key
+	case len(params) == 3 && params[0] == kdfPBKDF2:
+		if c.password == "" {
+			return nil, fmt.Errorf("value is encrypted with a password, set %s", PasswordEnv)
+		}
+		iterations, err := strconv.Atoi(params[1])
+		if err != nil || iterations < 1 {
+			return nil, fmt.Errorf("invalid iterations %q", params[1])
+		}
+		if iterations > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("iterations %d exceed the maximum of %d", iterations, maxPBKDF2Iterations)
+		}
+		salt, err := base64.RawStdEncoding.DecodeString(params[2])
+		if err != nil {
+			return nil, errors.New("invalid salt")
+		}
+		key = c.deriveKey(salt, iterations)
+	default:
+		return nil, fmt.Errorf("unsupported encryption %q", header)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errDecrypt
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errDecrypt
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(header))
+	if err != nil {
+		return nil, errDecrypt
+	}
+	return plaintext, nil
+}
+
+// EncryptValues returns the dotenv file src with every value that is not yet encrypted replaced by
+// its encrypted form, keeping keys and comments readable. The file is written in canonical form, like Format.
+func (c *Cipher) EncryptValues(src []byte) ([]byte, error) {
+	return c.mapValues(src, func(key, value string) (string, error) {
+		if IsEncrypted(value) {
+			return value, nil
+		}
+		return c.Encrypt([]byte(value))
+	})
+}
+
+// DecryptValues returns the dotenv file src with every encrypted value replaced by its plaintext.
+// The file is written in canonical form, like Format.
+func (c *Cipher) DecryptValues(src []byte) ([]byte, error) {
+	return c.mapValues(src, func(key, value string) (string, error) {
+		if !IsEncrypted(value) {
+			return value, nil
+		}
+		plaintext, err := c.Decrypt(value)
+		return string(plaintext), err
+	})
+}
+
+func (c *Cipher) mapValues(src []byte, f func(key, value string) (string, error)) ([]byte, error) {
+	lines, err := scanLines(bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n")))
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range lines {
+		if !line.entry {
+			continue
+		}
+		if lines[i].value, err = f(line.key, line.value); err != nil {
+			return nil, fmt.Errorf("%s: %s", line.key, err.Error())
+		}
+	}
+	return renderLines(lines)
+}
+
+// passwordSalt returns the salt used for every encryption of c.
+func (c *Cipher) passwordSalt() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.salt == nil {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		c.salt = salt
+	}
+	return c.salt, nil
+}
+
+func (c *Cipher) deriveKey(salt []byte, iterations int) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := strconv.Itoa(iterations) + ":" + string(salt)
+	if key, ok := c.derived[id]; ok {
+		return key
+	}
+	if c.derived == nil {
+		c.derived = make(map[string][]byte)
+	}
+	key := pbkdf2SHA256([]byte(c.password), salt, iterations, 32)
+	c.derived[id] = key
+	return key
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 derives a key of keyLen bytes from password and salt as defined by PBKDF2 in
+// RFC 8018, section 5.2, with HMAC-SHA256 as the pseudorandom function.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLen + prf.Size() - 1) / prf.Size()
+
+	dk := make([]byte, 0, blocks*prf.Size())
+	u := make([]byte, prf.Size())
+	t := make([]byte, prf.Size())
+	var index [4]byte
+	for block := 1; block <= blocks; block++ {
+		// U_1 = PRF(P, S || INT(i))
+		binary.BigEndian.PutUint32(index[:], uint32(block))
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(index[:])
+		u = prf.Sum(u[:0])
+		copy(t, u)
+
+		// U_j = PRF(P, U_{j-1}), T_i = U_1 ^ U_2 ^ ... ^ U_c
+		for j := 1; j < iterations; j++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for k := range t {
+				t[k] ^= u[k]
+			}
+		}
+		dk = append(dk, t...)
+	}
+	return dk[:keyLen]
+}
@@ -0,0 +1,254 @@
+package goenv
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func testKeyCipher(t *testing.T) *Cipher {
+	t.Helper()
+	key, _ := hex.DecodeString(testKey)
+	c, err := NewKeyCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func testPasswordCipher(t *testing.T, password string) *Cipher {
+	t.Helper()
+	c, err := NewPasswordCipher(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// keep the tests fast, the iterations are stored with every value
+	c.iterations = 1000
+	return c
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	tests := []struct {
+		password, salt string
+		iterations     int
+		expected       string
+	}{
+		// RFC 7914, section 11
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+	}
+
+	for _, tt := range tests {
+		got := hex.EncodeToString(pbkdf2SHA256([]byte(tt.password), []byte(tt.salt), tt.iterations, 64))
+		if got != tt.expected {
+			t.Errorf("pbkdf2SHA256(%q, %q, %d) = got %s, expected %s", tt.password, tt.salt, tt.iterations, got, tt.expected)
+		}
+	}
+}
+
+func TestCipher(t *testing.T) {
+	tests := []struct {
+		name   string
+		cipher *Cipher
+		prefix string
+	}{
+		{name: "Key", cipher: testKeyCipher(t), prefix: "enc:v1:key:"},
+		{name: "Password", cipher: testPasswordCipher(t, "hunter2"), prefix: "enc:v1:pbkdf2-sha256:1000:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext := "A=1\nB=\"two words\"\n"
+			encrypted, err := tt.cipher.Encrypt([]byte(plaintext))
+			if err != nil {
+				t.Fatalf("Encrypt() = failed with error: %v", err)
+			}
+			if !strings.HasPrefix(encrypted, tt.prefix) || strings.ContainsAny(encrypted, " #\"\n=") {
+				t.Errorf("Encrypt() = got %q, expected prefix %q and no characters that need quoting", encrypted, tt.prefix)
+			}
+
+			again, _ := tt.cipher.Encrypt([]byte(plaintext))
+			if again == encrypted {
+				t.Errorf("Encrypt() = got the same output twice, expected a random nonce")
+			}
+
+			got, err := tt.cipher.Decrypt(encrypted + "\n")
+			if err != nil {
+				t.Fatalf("Decrypt() = failed with error: %v", err)
+			}
+			if string(got) != plaintext {
+				t.Errorf("Decrypt() = got %q, expected %q", got, plaintext)
+			}
+
+			i := strings.LastIndexByte(encrypted, ':')
+			tampered := encrypted[:i+1] + "A" + encrypted[i+2:]
+			if encrypted[i+1] == 'A' {
+				tampered = encrypted[:i+1] + "B" + encrypted[i+2:]
+			}
+			if _, err := tt.cipher.Decrypt(tampered); !errors.Is(err, errDecrypt) {
+				t.Errorf("Decrypt() = got error %v for tampered payload, expected %v", err, errDecrypt)
+			}
+		})
+	}
+}
+
+func TestCipher_Errors(t *testing.T) {
+	key := testKeyCipher(t)
+	password := testPasswordCipher(t, "hunter2")
+	byKey, _ := key.Encrypt([]byte("x"))
+	byPassword, _ := password.Encrypt([]byte("x"))
+
+	otherKey, _ := NewKeyCipher(make([]byte, 32))
+	tests := []struct {
+		name     string
+		cipher   *Cipher
+		value    string
+		expected string
+	}{
+		{name: "Wrong key", cipher: otherKey, value: byKey, expected: errDecrypt.Error()},
+		{name: "Wrong password", cipher: testPasswordCipher(t, "hunter3"), value: byPassword, expected: errDecrypt.Error()},
+		{name: "Key for password", cipher: key, value: byPassword, expected: "value is encrypted with a password, set GOENV_PASSWORD"},
+		{name: "Password for key", cipher: password, value: byKey, expected: "value is encrypted with a key, set GOENV_KEY"},
+		{name: "Changed iterations", cipher: password, value: strings.Replace(byPassword, ":1000:", ":1001:", 1), expected: errDecrypt.Error()},
+		{name: "Too many iterations", cipher: password, value: strings.Replace(byPassword, ":1000:", ":2147483647:", 1), expected: "iterations 2147483647 exceed the maximum of 2400000"},
+		{name: "Not encrypted", cipher: key, value: "plain", expected: "not encrypted by goenv"},
+		{name: "Unknown scheme", cipher: key, value: "enc:v1:scrypt:abc", expected: `unsupported encryption "enc:v1:scrypt:"`},
+		{name: "Short payload", cipher: key, value: "enc:v1:key:AAAA", expected: errDecrypt.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cipher.Decrypt(tt.value)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Decrypt() = got error %v, expected %q", err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := NewKeyCipher([]byte("short")); err == nil {
+		t.Errorf("NewKeyCipher() = did not fail for a short key")
+	}
+	if _, err := NewPasswordCipher(""); err == nil {
+		t.Errorf("NewPasswordCipher() = did not fail for an empty password")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	raw, _ := hex.DecodeString(testKey)
+	for _, s := range []string{testKey, strings.ToUpper(testKey), "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=", "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8", " " + testKey + "\n"} {
+		key, err := ParseKey(s)
+		if err != nil || string(key) != string(raw) {
+			t.Errorf("ParseKey(%q) = got %x, %v, expected %x", s, key, err, raw)
+		}
+	}
+	if _, err := ParseKey("abcd"); err == nil {
+		t.Errorf("ParseKey() = did not fail for a short key")
+	}
+
+	generated, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseKey(generated); err != nil {
+		t.Errorf("ParseKey(GenerateKey()) = failed with error: %v", err)
+	}
+}
+
+func TestCipherFromEnv(t *testing.T) {
+	t.Setenv(KeyEnv, "")
+	t.Setenv(KeyEnv+"_FILE", "")
+	t.Setenv(PasswordEnv, "")
+	t.Setenv(PasswordEnv+"_FILE", "")
+
+	if _, err := CipherFromEnv(); !errors.Is(err, ErrNoKey) {
+		t.Errorf("CipherFromEnv() = got error %v, expected %v", err, ErrNoKey)
+	}
+
+	t.Setenv(PasswordEnv, "hunter2")
+	if c, err := CipherFromEnv(); err != nil || c.password != "hunter2" {
+		t.Errorf("CipherFromEnv() = got %v, %v, expected a password cipher", c, err)
+	}
+
+	t.Setenv(KeyEnv+"_FILE", writeTestFile(t, "key", testKey+"\n", 0o600))
+	if c, err := CipherFromEnv(); err != nil || hex.EncodeToString(c.key) != testKey {
+		t.Errorf("CipherFromEnv() = got %v, %v, expected the key from the file", c, err)
+	}
+
+	t.Setenv(KeyEnv, "not a key")
+	if _, err := CipherFromEnv(); err == nil || !strings.HasPrefix(err.Error(), "invalid GOENV_KEY") {
+		t.Errorf("CipherFromEnv() = got error %v, expected invalid GOENV_KEY", err)
+	}
+}
+
+func TestCipher_Values(t *testing.T) {
+	c := testPasswordCipher(t, "hunter2")
+	src := "# database\nDB_HOST=localhost\nDB_PASS=\"s3cret #1\" # rotate monthly\n"
+
+	encrypted, err := c.EncryptValues([]byte(src))
+	if err != nil {
+		t.Fatalf("EncryptValues() = failed with error: %v", err)
+	}
+	lines := strings.Split(string(encrypted), "\n")
+	if lines[0] != "# database" || !strings.HasPrefix(lines[1], "DB_HOST=enc:v1:") || !strings.HasSuffix(lines[2], " # rotate monthly") {
+		t.Errorf("EncryptValues() = got %q, expected readable keys and comments", encrypted)
+	}
+
+	again, err := c.EncryptValues(encrypted)
+	if err != nil || string(again) != string(encrypted) {
+		t.Errorf("EncryptValues() = encrypted values twice")
+	}
+
+	decrypted, err := c.DecryptValues(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptValues() = failed with error: %v", err)
+	}
+	if expected := "# database\nDB_HOST=localhost\nDB_PASS=\"s3cret #1\" # rotate monthly\n"; string(decrypted) != expected {
+		t.Errorf("DecryptValues() = got %q, expected %q", decrypted, expected)
+	}
+}
+
+func TestLoad_Encrypted(t *testing.T) {
+	t.Setenv(KeyEnv, testKey)
+	c := testKeyCipher(t)
+	dir := t.TempDir()
+
+	file, _ := c.Encrypt([]byte("GOENV_ENC_FILE=from file\n"))
+	values, _ := c.EncryptValues([]byte("GOENV_ENC_VALUE=from value\nGOENV_ENC_OTHER=other\n"))
+	// appended after encrypting, so the file mixes encrypted and plain values
+	values = append(values, "GOENV_ENC_PLAIN=plain\n"...)
+	os.WriteFile(filepath.Join(dir, ".env.enc"), []byte(file+"\n"), 0o600)
+	os.WriteFile(filepath.Join(dir, "values.env"), values, 0o600)
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	vars, err := Read()
+	if err != nil {
+		t.Fatalf("Read() = failed with error: %v", err)
+	}
+	if vars["GOENV_ENC_FILE"] != "from file" {
+		t.Errorf("Read() = got %v, expected the variables of .env.enc", vars)
+	}
+
+	vars, err = Read("values.env")
+	if err != nil {
+		t.Fatalf("Read() = failed with error: %v", err)
+	}
+	if vars["GOENV_ENC_VALUE"] != "from value" || vars["GOENV_ENC_OTHER"] != "other" || vars["GOENV_ENC_PLAIN"] != "plain" {
+		t.Errorf("Read() = got %v, expected decrypted values", vars)
+	}
+
+	t.Setenv(KeyEnv, "")
+	_, err = Read("values.env")
+	if expected := "goenv: Failed to load file 'values.env': GOENV_ENC_VALUE: " + ErrNoKey.Error(); err == nil || err.Error() != expected {
+		t.Errorf("Read() = got error %v, expected %q", err, expected)
+	}
+}
@@ -0,0 +1,92 @@
+package goenv
+
+import "sort"
+
+// Change is a single difference between two sets of environment variables.
+// Old is empty for added variables and New is empty for removed ones.
+type Change struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// ChangeSet holds the differences between two sets of environment variables, each sorted by key.
+type ChangeSet struct {
+	Added   []Change `json:"added"`
+	Removed []Change `json:"removed"`
+	Changed []Change `json:"changed"`
+}
+
+// Diff returns the variables that were added, removed or changed going from old to new.
+func Diff(old, new map[string]string) ChangeSet {
+	cs := ChangeSet{Added: []Change{}, Removed: []Change{}, Changed: []Change{}}
+	for key, oldValue := range old {
+		newValue, ok := new[key]
+		switch {
+		case !ok:
+			cs.Removed = append(cs.Removed, Change{Key: key, Old: oldValue})
+		case newValue != oldValue:
+			cs.Changed = append(cs.Changed, Change{Key: key, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range new {
+		if _, ok := old[key]; !ok {
+			cs.Added = append(cs.Added, Change{Key: key, New: newValue})
+		}
+	}
+
+	for _, changes := range [][]Change{cs.Added, cs.Removed, cs.Changed} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	}
+	return cs
+}
+
+// Empty reports whether the change set holds no differences.
+func (cs ChangeSet) Empty() bool {
+	return len(cs.Added) == 0 && len(cs.Removed) == 0 && len(cs.Changed) == 0
+}
+
+// Keys returns the keys of every change, sorted.
+func (cs ChangeSet) Keys() []string {
+	keys := make([]string, 0, len(cs.Added)+len(cs.Removed)+len(cs.Changed))
+	for _, changes := range [][]Change{cs.Added, cs.Removed, cs.Changed} {
+		for _, c := range changes {
+			keys = append(keys, c.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Masked returns a copy of the change set with every value replaced by "***",
+// so it can be logged without leaking secrets.
+func (cs ChangeSet) Masked() ChangeSet {
+	mask := func(changes []Change) []Change {
+		masked := make([]Change, len(changes))
+		for i, c := range changes {
+			masked[i] = Change{Key: c.Key}
+			if c.Old != "" {
+				masked[i].Old = redactedValue
+			}
+			if c.New != "" {
+				masked[i].New = redactedValue
+			}
+		}
+		return masked
+	}
+	return ChangeSet{Added: mask(cs.Added), Removed: mask(cs.Removed), Changed: mask(cs.Changed)}
+}
+
+// filter returns the changes of keys.
+func (cs ChangeSet) filter(keys map[string]bool) ChangeSet {
+	keep := func(changes []Change) []Change {
+		kept := []Change{}
+		for _, c := range changes {
+			if keys[c.Key] {
+				kept = append(kept, c)
+			}
+		}
+		return kept
+	}
+	return ChangeSet{Added: keep(cs.Added), Removed: keep(cs.Removed), Changed: keep(cs.Changed)}
+}
@@ -0,0 +1,76 @@
+package goenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new map[string]string
+		expected ChangeSet
+	}{
+		{
+			name:     "Equal",
+			old:      map[string]string{"A": "1"},
+			new:      map[string]string{"A": "1"},
+			expected: ChangeSet{Added: []Change{}, Removed: []Change{}, Changed: []Change{}},
+		},
+		{
+			name: "Added, removed and changed",
+			old:  map[string]string{"A": "1", "B": "2", "C": "3", "D": "4"},
+			new:  map[string]string{"A": "1", "C": "30", "E": "5", "B2": ""},
+			expected: ChangeSet{
+				Added:   []Change{{Key: "B2"}, {Key: "E", New: "5"}},
+				Removed: []Change{{Key: "B", Old: "2"}, {Key: "D", Old: "4"}},
+				Changed: []Change{{Key: "C", Old: "3", New: "30"}},
+			},
+		},
+		{
+			name: "Nil maps",
+			old:  nil,
+			new:  map[string]string{"A": "1"},
+			expected: ChangeSet{
+				Added:   []Change{{Key: "A", New: "1"}},
+				Removed: []Change{},
+				Changed: []Change{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Diff() = got:\n%+v\nexpected:\n%+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChangeSet(t *testing.T) {
+	cs := Diff(map[string]string{"B": "secret", "C": "x"}, map[string]string{"A": "", "C": "y"})
+
+	if cs.Empty() {
+		t.Errorf("ChangeSet.Empty() = got true, expected false")
+	}
+	if !Diff(nil, nil).Empty() {
+		t.Errorf("ChangeSet.Empty() = got false for no changes")
+	}
+	if got, expected := cs.Keys(), []string{"A", "B", "C"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("ChangeSet.Keys() = got %v, expected %v", got, expected)
+	}
+
+	expected := ChangeSet{
+		Added:   []Change{{Key: "A"}},
+		Removed: []Change{{Key: "B", Old: "***"}},
+		Changed: []Change{{Key: "C", Old: "***", New: "***"}},
+	}
+	if got := cs.Masked(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ChangeSet.Masked() = got %+v, expected %+v", got, expected)
+	}
+	if cs.Changed[0].Old != "x" {
+		t.Errorf("ChangeSet.Masked() = modified the original change set")
+	}
+}
@@ -0,0 +1,65 @@
+package goenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// redactedValue replaces the values of sensitive fields.
+const redactedValue = "***"
+
+// Dump writes every field of the struct v that is read from an environment variable to w,
+// one per line with its Go field path, key and current value. The values of fields tagged
+// sensitive are masked, so the output is safe to log.
+//
+// v may be a struct or a pointer to a struct. The options are the same as for Struct.
+func Dump(w io.Writer, v any, opts ...Option) error {
+	val, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	var tagErr error
+	walker := structWalker{
+		opts: newOptions(opts),
+		field: func(f envField, err error) {
+			if err != nil {
+				if tagErr == nil {
+					tagErr = fmt.Errorf("goenv - error on field %s: %s", f.path, err.Error())
+				}
+				return
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", f.path, f.key, displayValue(f))
+		},
+	}
+	walker.walk(val, "", walker.opts.prefix)
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return tagErr
+}
+
+// Redacted returns the output of Dump as a string. It returns an empty string if v is not a struct.
+func Redacted(v any, opts ...Option) string {
+	var b strings.Builder
+	Dump(&b, v, opts...)
+	return b.String()
+}
+
+// displayValue formats the current value of f, masking it if f is sensitive and not empty.
+func displayValue(f envField) string {
+	if f.tag.sensitive {
+		if f.value.IsZero() {
+			return ""
+		}
+		return redactedValue
+	}
+	if value, err := formatFieldValue(f.value, f.tag); err == nil {
+		return value
+	}
+	return fmt.Sprint(f.value.Interface())
+}
@@ -0,0 +1,174 @@
+package goenv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+type testSecretConfig struct {
+	Host     string `goenv:"TEST_DB_HOST"`
+	Password string `goenv:"TEST_DB_PASS,sensitive"`
+	Token    string `goenv:"TEST_TOKEN,sensitive"`
+	Pin      int    `goenv:"TEST_PIN,sensitive"`
+}
+
+func TestStructSensitive(t *testing.T) {
+	os.Setenv("TEST_DB_PASS", "s3cret")
+	os.Setenv("TEST_PIN", "hunter2")
+	t.Cleanup(func() {
+		os.Unsetenv("TEST_DB_PASS")
+		os.Unsetenv("TEST_PIN")
+	})
+
+	var cfg testSecretConfig
+	err := Struct(&cfg)
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("Struct() error = %v, want one field error", err)
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("Struct() error = %q, contains sensitive value", err.Error())
+	}
+	if errs[0].Value != "***" {
+		t.Errorf("FieldError.Value = %q, want %q", errs[0].Value, "***")
+	}
+	if want := `goenv - error on field Pin: invalid int value "***"`; err.Error() != want {
+		t.Errorf("Struct() error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestStructSensitive_Rules(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		input any
+		want  string
+	}{
+		{
+			name:  "File exists",
+			value: "hunter2",
+			input: &struct {
+				Secret string `goenv:"TEST_SECRET,sensitive,file_exists"`
+			}{},
+			want: "goenv - error on field Secret: file must exist: stat ***: no such file or directory",
+		},
+		{
+			name:  "Short value in oneof",
+			value: "e",
+			input: &struct {
+				Secret string `goenv:"TEST_SECRET,sensitive,oneof=abc|def"`
+			}{},
+			want: "goenv - error on field Secret: must be one of abc|def",
+		},
+		{
+			name:  "Digit in min",
+			value: "1",
+			input: &struct {
+				Secret int `goenv:"TEST_SECRET,sensitive,min=10"`
+			}{},
+			want: "goenv - error on field Secret: must be at least 10",
+		},
+		{
+			name:  "Invalid value",
+			value: "hunter2",
+			input: &struct {
+				Secret float64 `goenv:"TEST_SECRET,sensitive"`
+			}{},
+			want: `goenv - error on field Secret: invalid float value "***"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.input, WithEnvironment(map[string]string{"TEST_SECRET": tt.value}))
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("Struct() error = %v, want %q", err, tt.want)
+			}
+
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Value != "***" {
+				t.Errorf("FieldError = %+v, want the value masked", fe)
+			}
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				t.Errorf("Struct() error = wraps %v, which holds the sensitive value", pathErr)
+			}
+		})
+	}
+
+	err := Struct(&struct {
+		Secret string `goenv:"TEST_SECRET,sensitive,file_exists"`
+	}{}, WithEnvironment(map[string]string{"TEST_SECRET": "hunter2"}))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Struct() error = %v, want it to wrap %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestStructUnset(t *testing.T) {
+	secret := writeTestFile(t, "token", "t0ken\n", 0o600)
+	os.Setenv("TEST_DB_PASS", "s3cret")
+	os.Setenv("TEST_TOKEN_FILE", secret)
+	os.Setenv("TEST_DB_HOST", "localhost")
+	t.Cleanup(func() {
+		os.Unsetenv("TEST_DB_PASS")
+		os.Unsetenv("TEST_TOKEN_FILE")
+		os.Unsetenv("TEST_DB_HOST")
+	})
+
+	var cfg struct {
+		Host     string `goenv:"TEST_DB_HOST"`
+		Password string `goenv:"TEST_DB_PASS,sensitive,unset"`
+		Token    string `goenv:"TEST_TOKEN,unset"`
+	}
+	if err := Struct(&cfg); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+
+	if cfg.Password != "s3cret" || cfg.Token != "t0ken" {
+		t.Errorf("cfg = %+v, want password and token set", cfg)
+	}
+	for _, key := range []string{"TEST_DB_PASS", "TEST_TOKEN_FILE"} {
+		if _, found := os.LookupEnv(key); found {
+			t.Errorf("%s is still set after Struct()", key)
+		}
+	}
+	if _, found := os.LookupEnv("TEST_DB_HOST"); !found {
+		t.Errorf("TEST_DB_HOST was unset without the unset option")
+	}
+}
+
+func TestDump(t *testing.T) {
+	cfg := testSecretConfig{
+		Host:     "localhost",
+		Password: "s3cret",
+		Pin:      1234,
+	}
+
+	got := Redacted(cfg)
+	want := strings.Join([]string{
+		"Host      TEST_DB_HOST  localhost",
+		"Password  TEST_DB_PASS  ***",
+		"Token     TEST_TOKEN    ",
+		"Pin       TEST_PIN      ***",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("Redacted() = \n%s\nwant:\n%s", got, want)
+	}
+
+	var b strings.Builder
+	if err := Dump(&b, &cfg); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("Dump() = \n%s\nwant:\n%s", b.String(), want)
+	}
+
+	if err := Dump(&b, "not a struct"); err == nil {
+		t.Errorf("Dump() error = nil, want error for non-struct")
+	}
+}
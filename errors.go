@@ -0,0 +1,51 @@
+package goenv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissingRequired is the cause of a FieldError for a required field whose environment variable is not set.
+var ErrMissingRequired = errors.New("missing required env var")
+
+// FieldError describes why a single struct field could not be populated.
+type FieldError struct {
+	// Field is the Go path of the field, e.g. "Database.Port".
+	Field string
+	// Key is the environment variable the field is read from.
+	Key string
+	// Value is the raw value that was read, if any.
+	Value string
+	// Err is the underlying cause.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("goenv - error on field %s: %s", e.Field, e.Err.Error())
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors collects every FieldError encountered while populating a struct.
+//
+// Use errors.As to retrieve it from the error returned by Struct.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
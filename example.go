@@ -0,0 +1,80 @@
+package goenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GenerateExample writes an example environment file for the struct v to w, such as a .env.example.
+//
+// Every field that Struct would read is written as a KEY=value line set to its default, preceded by
+// comments with the Go field path, the Go type, whether the variable is required, and the description
+// from the field's `desc:"..."` tag. The output is valid input for Load.
+//
+// v may be a struct or a pointer to a struct. The options are the same as for Struct.
+//
+// Example output:
+//
+//	# Database.Port (int)
+//	# Port of the database server
+//	DB_PORT=5432
+func GenerateExample(w io.Writer, v any, opts ...Option) error {
+	val, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	var entries []string
+	var firstErr error
+	walker := structWalker{
+		opts: newOptions(opts),
+		field: func(f envField, err error) {
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("goenv - error on field %s: %s", f.path, err.Error())
+				}
+				return
+			}
+			entry, err := exampleEntry(f)
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("goenv - error on field %s: %s", f.path, err.Error())
+			}
+			entries = append(entries, entry)
+		},
+	}
+	walker.walk(val, "", walker.opts.prefix)
+	if firstErr != nil {
+		return firstErr
+	}
+
+	_, err = io.WriteString(w, strings.Join(entries, "\n"))
+	return err
+}
+
+func exampleEntry(f envField) (string, error) {
+	var b strings.Builder
+
+	attrs := []string{f.value.Type().String()}
+	if f.tag.required {
+		attrs = append(attrs, "required")
+	}
+	fmt.Fprintf(&b, "# %s (%s)\n", f.path, strings.Join(attrs, ", "))
+
+	if desc := f.field.Tag.Get("desc"); desc != "" {
+		for _, line := range strings.Split(desc, "\n") {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+	}
+
+	if len(f.aliases) > 0 {
+		fmt.Fprintf(&b, "# Deprecated: %s\n", strings.Join(f.aliases, ", "))
+	}
+
+	value, err := formatValue(f.tag.defaultValue)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "%s=%s\n", f.key, value)
+	return b.String(), nil
+}
@@ -0,0 +1,68 @@
+package goenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateExample(t *testing.T) {
+	type databaseConfig struct {
+		Host string `goenv:"HOST,required" desc:"Hostname of the database server"`
+		Port int    `goenv:"PORT,default=5432"`
+	}
+	type config struct {
+		Env      string         `goenv:"ENV,default=development" desc:"Deployment environment"`
+		From     string         `goenv:"MAILER_FROM,default=Appname <noreply@appname.com> #1"`
+		Timeout  time.Duration  `goenv:"TIMEOUT,default=30s"`
+		Password string         `goenv:"PASSWORD,sensitive"`
+		Primary  databaseConfig `envPrefix:"PRIMARY_"`
+		Ignored  string
+	}
+
+	var b strings.Builder
+	if err := GenerateExample(&b, config{}, WithPrefix("APP_")); err != nil {
+		t.Fatalf("GenerateExample() error = %v", err)
+	}
+
+	want := `# Env (string)
+# Deployment environment
+APP_ENV=development
+
+# From (string)
+APP_MAILER_FROM="Appname <noreply@appname.com> #1"
+
+# Timeout (time.Duration)
+APP_TIMEOUT=30s
+
+# Password (string)
+APP_PASSWORD=""
+
+# Primary.Host (string, required)
+# Hostname of the database server
+APP_PRIMARY_HOST=""
+
+# Primary.Port (int)
+APP_PRIMARY_PORT=5432
+`
+	if b.String() != want {
+		t.Errorf("GenerateExample() = \n%s\nwant:\n%s", b.String(), want)
+	}
+
+	got, err := parseInput([]byte(b.String()))
+	if err != nil {
+		t.Fatalf("parseInput() error = %v", err)
+	}
+	expected := map[string]string{
+		"APP_ENV":          "development",
+		"APP_MAILER_FROM":  "Appname <noreply@appname.com> #1",
+		"APP_TIMEOUT":      "30s",
+		"APP_PASSWORD":     "",
+		"APP_PRIMARY_HOST": "",
+		"APP_PRIMARY_PORT": "5432",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseInput() = %v, want %v", got, expected)
+	}
+}
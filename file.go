@@ -0,0 +1,88 @@
+package goenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const defaultMaxFileSize = 1 << 20
+
+// lookupEnv retrieves the value of environment variable key. If key is unset or empty, but key+"_FILE"
+// holds a path, then the contents of that file is returned instead. This is how Docker and Kubernetes
+// secrets are commonly passed to a process.
+func (o *options) lookupEnv(key string) (string, bool, error) {
+	v, found := o.lookup(key)
+	if found && v != "" {
+		return v, true, nil
+	}
+
+	path, ok := o.lookup(key + "_FILE")
+	if !ok || path == "" {
+		return v, found, nil
+	}
+
+	contents, err := o.readSecretFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s_FILE: %s", key, err.Error())
+	}
+	return contents, true, nil
+}
+
+// lookupField retrieves the value of f from the first of its key and aliases that is set,
+// and returns the key it was read from.
+func (o *options) lookupField(f envField) (string, string, bool, error) {
+	value, found, err := o.lookupEnv(f.key)
+	if err != nil || (found && value != "") {
+		return f.key, value, found, err
+	}
+
+	for _, alias := range f.aliases {
+		v, ok, err := o.lookupEnv(alias)
+		if err != nil || (ok && v != "") {
+			return alias, v, ok, err
+		}
+	}
+
+	return f.key, value, found, nil
+}
+
+// lookup retrieves key from the configured environment without any _FILE indirection.
+func (o *options) lookup(key string) (string, bool) {
+	if o.env != nil {
+		v, found := o.env[key]
+		return v, found
+	}
+	return os.LookupEnv(key)
+}
+
+// readSecretFile returns the contents of the file at path without trailing line breaks.
+func (o *options) readSecretFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+	if o.filePerm != 0 && info.Mode().Perm()&^o.filePerm != 0 {
+		return "", fmt.Errorf("%s has permissions %s, want at most %s", path, info.Mode().Perm(), o.filePerm)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, o.maxFileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > o.maxFileSize {
+		return "", fmt.Errorf("%s exceeds the maximum size of %d bytes", path, o.maxFileSize)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,160 @@
+package goenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, name, contents string, perm os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), perm); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatalf("failed to chmod %s: %v", path, err)
+	}
+	return path
+}
+
+func TestGettersFile(t *testing.T) {
+	secret := writeTestFile(t, "db_pass", "s3cret\n", 0o600)
+	port := writeTestFile(t, "port", "8080\r\n", 0o600)
+
+	os.Setenv("TEST_DB_PASS_FILE", secret)
+	os.Setenv("TEST_PORT_FILE", port)
+	os.Setenv("TEST_SET_FILE", secret)
+	os.Setenv("TEST_SET", "direct")
+	os.Setenv("TEST_MISSING_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Cleanup(func() {
+		for _, k := range []string{"TEST_DB_PASS_FILE", "TEST_PORT_FILE", "TEST_SET_FILE", "TEST_SET", "TEST_MISSING_FILE"} {
+			os.Unsetenv(k)
+		}
+	})
+
+	if got := String("TEST_DB_PASS", "fallback"); got != "s3cret" {
+		t.Errorf("String() = %v, want %v", got, "s3cret")
+	}
+	if got := Int("TEST_PORT", 0); got != 8080 {
+		t.Errorf("Int() = %v, want %v", got, 8080)
+	}
+	if got := String("TEST_SET", "fallback"); got != "direct" {
+		t.Errorf("String() = %v, want %v", got, "direct")
+	}
+	if got := String("TEST_MISSING", "fallback"); got != "fallback" {
+		t.Errorf("String() = %v, want %v", got, "fallback")
+	}
+	if got := MustString("TEST_DB_PASS"); got != "s3cret" {
+		t.Errorf("MustString() = %v, want %v", got, "s3cret")
+	}
+}
+
+func TestStructFile(t *testing.T) {
+	secret := writeTestFile(t, "db_pass", "s3cret\n\n", 0o600)
+	cert := writeTestFile(t, "cert.pem", "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----\n", 0o644)
+	large := writeTestFile(t, "large", strings.Repeat("x", 64), 0o600)
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		opts  []Option
+		fail  bool
+		check func(t *testing.T, cfg *testFileConfig)
+	}{
+		{
+			name: "Read KEY_FILE and file option",
+			env: map[string]string{
+				"TEST_DB_PASS_FILE": secret,
+				"TEST_TLS_CERT":     cert,
+			},
+			check: func(t *testing.T, cfg *testFileConfig) {
+				if cfg.Password != "s3cret" {
+					t.Errorf("Password = %q, want %q", cfg.Password, "s3cret")
+				}
+				if cfg.Cert != "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----" {
+					t.Errorf("Cert = %q", cfg.Cert)
+				}
+			},
+		},
+		{
+			name: "Variable takes precedence over KEY_FILE",
+			env: map[string]string{
+				"TEST_DB_PASS":      "direct",
+				"TEST_DB_PASS_FILE": secret,
+			},
+			check: func(t *testing.T, cfg *testFileConfig) {
+				if cfg.Password != "direct" {
+					t.Errorf("Password = %q, want %q", cfg.Password, "direct")
+				}
+			},
+		},
+		{
+			name: "Missing KEY_FILE",
+			env: map[string]string{
+				"TEST_DB_PASS_FILE": filepath.Join(t.TempDir(), "missing"),
+			},
+			fail: true,
+		},
+		{
+			name: "File exceeds size limit",
+			env: map[string]string{
+				"TEST_DB_PASS_FILE": large,
+			},
+			opts: []Option{WithMaxFileSize(32)},
+			fail: true,
+		},
+		{
+			name: "File with too open permissions",
+			env: map[string]string{
+				"TEST_DB_PASS_FILE": secret,
+				"TEST_TLS_CERT":     cert,
+			},
+			opts: []Option{WithFilePermissions(0o600)},
+			fail: true,
+		},
+		{
+			name: "Path is a directory",
+			env: map[string]string{
+				"TEST_TLS_CERT": t.TempDir(),
+			},
+			fail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			t.Cleanup(func() {
+				for k := range tt.env {
+					os.Unsetenv(k)
+				}
+			})
+
+			var cfg testFileConfig
+			err := Struct(&cfg, tt.opts...)
+			if tt.fail {
+				var errs ValidationErrors
+				if !errors.As(err, &errs) {
+					t.Errorf("Struct() error = %v, want ValidationErrors", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Struct() error = %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, &cfg)
+			}
+		})
+	}
+}
+
+type testFileConfig struct {
+	Password string `goenv:"TEST_DB_PASS"`
+	Cert     string `goenv:"TEST_TLS_CERT,file"`
+}
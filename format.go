@@ -0,0 +1,133 @@
+package goenv
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type formatLine struct {
+	// key is empty for comment and blank lines, which only set comment.
+	key     string
+	value   string
+	comment string
+	entry   bool
+	// line and column locate the key of a variable.
+	line   int
+	column int
+}
+
+// Format returns the content of a dotenv file in canonical form: one KEY=value pair per line with
+// the least quoting needed, comments on their own line or after the value, at most one blank line
+// in a row and "\n" line endings. If sortKeys is true, the keys of every block of consecutive
+// variables are sorted, while comments and blank lines stay in place.
+//
+// The result is read by Load as exactly the same variables as src. Format returns an error if src
+// cannot be loaded.
+func Format(src []byte, sortKeys bool) ([]byte, error) {
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+	lines, err := scanLines(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if sortKeys {
+		for start := 0; start < len(lines); start++ {
+			end := start
+			for end < len(lines) && lines[end].entry {
+				end++
+			}
+			block := lines[start:end]
+			sort.SliceStable(block, func(i, j int) bool { return block[i].key < block[j].key })
+			start = end
+		}
+	}
+
+	out, err := renderLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	// guard against formatting ever changing what is loaded
+	before, _ := parseInput(src)
+	after, err := parseInput(out)
+	if err != nil || !maps.Equal(before, after) {
+		return nil, fmt.Errorf("goenv: formatting changed the variables of the file")
+	}
+
+	return out, nil
+}
+
+// renderLines writes lines in canonical form, collapsing blank lines.
+func renderLines(lines []formatLine) ([]byte, error) {
+	var buf bytes.Buffer
+	blank := false
+	for _, line := range lines {
+		if !line.entry && line.comment == "" {
+			blank = buf.Len() > 0
+			continue
+		}
+		if blank {
+			buf.WriteByte('\n')
+			blank = false
+		}
+
+		if !line.entry {
+			buf.WriteString(line.comment)
+			buf.WriteByte('\n')
+			continue
+		}
+
+		value, err := formatValue(line.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", line.key, err.Error())
+		}
+		buf.WriteString(line.key + "=" + value)
+		if line.comment != "" {
+			buf.WriteString(" " + line.comment)
+		}
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// scanLines splits src in to blank lines, comment lines and variables, reading the variables the same
+// way as parseInput.
+func scanLines(src []byte) ([]formatLine, error) {
+	var lines []formatLine
+	lineNo := 1
+	for len(src) > 0 {
+		line, next, _ := bytes.Cut(src, []byte("\n"))
+		trimmed := strings.TrimSpace(string(line))
+		if trimmed == "" || trimmed[0] == '#' {
+			lines = append(lines, formatLine{comment: trimmed})
+			src = next
+			lineNo++
+			continue
+		}
+
+		key, rest, err := findKey(src)
+		if err == nil && strings.Contains(key, "\n") {
+			err = missingDelimeter
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: malformed line: %s", lineNo, err.Error())
+		}
+
+		value, comment, rest, err := findValue(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: malformed value: %s", lineNo, err.Error())
+		}
+
+		column := len(line) - len(bytes.TrimLeftFunc(line, unicode.IsSpace)) + 1
+		lines = append(lines, formatLine{key: key, value: value, comment: comment, entry: true, line: lineNo, column: column})
+		lineNo += bytes.Count(src[:len(src)-len(rest)], []byte("\n"))
+		src = rest
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,132 @@
+package goenv
+
+import (
+	"maps"
+	"os"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		sortKeys bool
+		expected string
+	}{
+		{
+			name:     "Spaces around delimiter",
+			src:      "KEY = value\n  OTHER=  x  \n",
+			expected: "KEY=value\nOTHER=x\n",
+		},
+		{
+			name:     "Minimal quoting",
+			src:      "A=\"plain\"\nB=\" padded \"\nC=\"\"\nD=\"a #b\"\nE=\"a#b\"\n",
+			expected: "A=plain\nB=\" padded \"\nC=\"\"\nD=\"a #b\"\nE=a#b\n",
+		},
+		{
+			name:     "Comments",
+			src:      "  # header  \nA=1   # one\nB=\"two\"  # two\nC=\"three\" ignored\n",
+			expected: "# header\nA=1 # one\nB=two # two\nC=three\n",
+		},
+		{
+			name:     "Blank lines",
+			src:      "\n\nA=1\n\n\n\nB=2\n\n",
+			expected: "A=1\n\nB=2\n",
+		},
+		{
+			name:     "Line endings",
+			src:      "A=1\r\nB=\"x y\"\r\n",
+			expected: "A=1\nB=x y\n",
+		},
+		{
+			name:     "Multiline value",
+			src:      "A=\"one\ntwo\"\nB=2",
+			expected: "A=\"one\ntwo\"\nB=2\n",
+		},
+		{
+			name:     "Empty value reads next line",
+			src:      "A=\nB=2\n",
+			expected: "A=B=2\n",
+		},
+		{
+			name:     "Sort within blocks",
+			src:      "# b\nZ=1\nA=2 # a\n\nY=3\nB=4\n# c\nX=5\nC=6\n",
+			sortKeys: true,
+			expected: "# b\nA=2 # a\nZ=1\n\nB=4\nY=3\n# c\nC=6\nX=5\n",
+		},
+		{
+			name:     "Sort keeps order of duplicates",
+			src:      "B=1\nA=1\nB=2\n",
+			sortKeys: true,
+			expected: "A=1\nB=1\nB=2\n",
+		},
+		{
+			name:     "Empty file",
+			src:      "\n# \n",
+			expected: "#\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format([]byte(tt.src), tt.sortKeys)
+			if err != nil {
+				t.Fatalf("Format() = failed with error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Format() = got:\n%q\nexpected:\n%q", got, tt.expected)
+			}
+
+			before, _ := parseInput([]byte(tt.src))
+			after, _ := parseInput(got)
+			if !maps.Equal(before, after) {
+				t.Errorf("Format() = changed variables from %v to %v", before, after)
+			}
+		})
+	}
+}
+
+func TestFormat_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "Missing delimiter",
+			src:      "A=1\nB\n",
+			expected: "line 2: malformed line: Missing '=' in environment variable",
+		},
+		{
+			name:     "Missing end quote",
+			src:      "A=1\n\nB=\"open\n",
+			expected: "line 3: malformed value: Missing end quote '\"' in environment variable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Format([]byte(tt.src), false)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Format() = got error %v, expected %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormat_Testdata(t *testing.T) {
+	for _, file := range []string{".env", "testdata/.env.ci", "testdata/.env.development", "testdata/.env.staging", "testdata/.env.test"} {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Format(src, false)
+		if err != nil {
+			t.Fatalf("Format(%s) = failed with error: %v", file, err)
+		}
+		again, err := Format(got, false)
+		if err != nil || string(again) != string(got) {
+			t.Errorf("Format(%s) = not idempotent:\n%s\n%s", file, got, again)
+		}
+	}
+}
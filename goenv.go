@@ -1,16 +1,20 @@
 // Package goenv provides functions for retrieving values from environment variables with fallback values.
+//
+// If a variable KEY is unset or empty, but KEY_FILE holds the path of a file, then the
+// contents of that file is used as the value, with trailing line breaks trimmed.
+// This is how Docker and Kubernetes secrets are commonly passed to a process.
 package goenv
 
 import (
 	"fmt"
-	"os"
+	"maps"
 	"strconv"
 	"time"
 )
 
 // String retrives the value of environment variable `k`. If no value is found, then the fallback value is returned.
 func String(k, f string) string {
-	v, found := os.LookupEnv(k)
+	v, found, _ := defaultOptions.lookupEnv(k)
 	if !found || v == "" {
 		return f
 	}
@@ -21,7 +25,7 @@ func String(k, f string) string {
 // into `time.Duration`. Should this fail, then the fallback value is returned. If the variable is not present, then
 // the fallback value is returned.
 func Duration(k string, f time.Duration) time.Duration {
-	v, found := os.LookupEnv(k)
+	v, found, _ := defaultOptions.lookupEnv(k)
 	if !found {
 		return f
 	}
@@ -32,10 +36,40 @@ func Duration(k string, f time.Duration) time.Duration {
 	return dur
 }
 
+// ExtendedDuration retrieves the value of environment variable `k` like Duration, but parses it with
+// ParseDuration, which also accepts days ("7d") and weeks ("2w"). If the variable is not present or cannot
+// be parsed, then the fallback value is returned.
+func ExtendedDuration(k string, f time.Duration) time.Duration {
+	v, found, _ := defaultOptions.lookupEnv(k)
+	if !found {
+		return f
+	}
+	dur, err := ParseDuration(v)
+	if err != nil {
+		return f
+	}
+	return dur
+}
+
+// Bytes retrieves the value of environment variable `k`. If the variable is present, then the value is parsed
+// into a ByteSize, accepting values such as "512MiB" or "2GB". If the variable is not present or cannot be parsed,
+// then the fallback is returned.
+func Bytes(k string, f ByteSize) ByteSize {
+	v, found, _ := defaultOptions.lookupEnv(k)
+	if !found {
+		return f
+	}
+	size, err := ParseByteSize(v)
+	if err != nil {
+		return f
+	}
+	return size
+}
+
 // Int retrieves the value of environment variable `k`. If the variable is present, then the value is parsed
 // into type `int`. If the variable is not present, then the fallback is returned.
 func Int(k string, f int) int {
-	v, found := os.LookupEnv(k)
+	v, found, _ := defaultOptions.lookupEnv(k)
 	if !found {
 		return f
 	}
@@ -47,13 +81,14 @@ func Int(k string, f int) int {
 }
 
 // Bool retrieves the value of environment variable `k`. If the variable is present, then the value is parsed
-// into type `bool`. If the variable is not present, then the fallback is returned.
+// into type `bool` with ParseBool, which also accepts words such as "yes" and "off". If the variable is not present,
+// then the fallback is returned.
 func Bool(k string, f bool) bool {
-	v, found := os.LookupEnv(k)
+	v, found, _ := defaultOptions.lookupEnv(k)
 	if !found {
 		return f
 	}
-	b, err := strconv.ParseBool(v)
+	b, err := ParseBool(v)
 	if err != nil {
 		return f
 	}
@@ -76,5 +111,44 @@ func MustString(k string) string {
 // If multiple files are provided the first file is loaded fully,
 // while only keys not already in the environment are loaded for the remaining files.
 func Load(filenames ...string) error {
-	return loadFiles(filenames)
+	return loadFiles(filenames, false)
+}
+
+// Overload loads the content of 1 or more files in to the current environment like Load,
+// but every file overrides keys already in the environment, not only the first.
+//
+// If a key is in multiple files, the value from the first file is still used.
+func Overload(filenames ...string) error {
+	return loadFiles(filenames, true)
+}
+
+// Read returns the variables of 1 or more files without modifying the environment.
+//
+// If no files are provided, Read defaults to ".env".
+//
+// If a key is in multiple files, the value from the first file is used.
+func Read(filenames ...string) (map[string]string, error) {
+	return resolveFiles(filenames, func(string) (string, bool) { return "", false }, false)
+}
+
+// Merge returns a copy of env with the variables of 1 or more files applied the same way Load applies them
+// to the current environment. With override set, they are applied the way Overload applies them.
+//
+// If no files are provided, Merge defaults to ".env".
+func Merge(env map[string]string, override bool, filenames ...string) (map[string]string, error) {
+	lookup := func(key string) (string, bool) {
+		v, found := env[key]
+		return v, found
+	}
+	vars, err := resolveFiles(filenames, lookup, override)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := maps.Clone(env)
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	maps.Copy(merged, vars)
+	return merged, nil
 }
@@ -315,6 +315,38 @@ func TestBool(t *testing.T) {
 			set:  true,
 			want: false, // assuming your implementation doesn't trim whitespace
 		},
+		{
+			name: "Get a variable with yes",
+			k:    "TEST_YES_KEY",
+			f:    false,
+			v:    "yes",
+			set:  true,
+			want: true,
+		},
+		{
+			name: "Get a variable with OFF (uppercase)",
+			k:    "TEST_OFF_KEY",
+			f:    true,
+			v:    "OFF",
+			set:  true,
+			want: false,
+		},
+		{
+			name: "Get a variable with Enabled (mixed case)",
+			k:    "TEST_ENABLED_KEY",
+			f:    false,
+			v:    "Enabled",
+			set:  true,
+			want: true,
+		},
+		{
+			name: "Get a variable with n",
+			k:    "TEST_N_KEY",
+			f:    true,
+			v:    "n",
+			set:  true,
+			want: false,
+		},
 		{
 			name: "Get a variable with numeric string (not 0 or 1)",
 			k:    "TEST_NUMERIC_KEY",
@@ -337,3 +369,22 @@ func TestBool(t *testing.T) {
 		})
 	}
 }
+
+func TestExtendedDuration(t *testing.T) {
+	os.Setenv("TEST_RETENTION", "7d")
+	os.Setenv("TEST_INVALID_RETENTION", "a week")
+	t.Cleanup(func() {
+		os.Unsetenv("TEST_RETENTION")
+		os.Unsetenv("TEST_INVALID_RETENTION")
+	})
+
+	if got := ExtendedDuration("TEST_RETENTION", time.Hour); got != 7*24*time.Hour {
+		t.Errorf("ExtendedDuration() = %v, want %v", got, 7*24*time.Hour)
+	}
+	if got := ExtendedDuration("TEST_INVALID_RETENTION", time.Hour); got != time.Hour {
+		t.Errorf("ExtendedDuration() = %v, want %v", got, time.Hour)
+	}
+	if got := Duration("TEST_RETENTION", time.Hour); got != time.Hour {
+		t.Errorf("Duration() = %v, want %v", got, time.Hour)
+	}
+}
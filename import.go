@@ -0,0 +1,296 @@
+package goenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Entry is a variable read from a file, together with the position of its key.
+type Entry struct {
+	Key   string
+	Value string
+	Pos   Position
+}
+
+// EntryMap returns the variables of entries as a map. If a key occurs more than once,
+// the last entry wins, as it does when a dotenv file is loaded.
+func EntryMap(entries []Entry) map[string]string {
+	env := make(map[string]string, len(entries))
+	for _, e := range entries {
+		env[e.Key] = e.Value
+	}
+	return env
+}
+
+// ParseEnv reads the variables of a dotenv file in the order they are defined, with the same
+// rules as Load. filename is only used for the positions and errors.
+func ParseEnv(filename string, src []byte) ([]Entry, error) {
+	lines, err := scanLines(bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n")))
+	if err != nil {
+		return nil, parseError(filename, err)
+	}
+
+	var entries []Entry
+	for _, line := range lines {
+		if line.entry {
+			entries = append(entries, Entry{
+				Key:   line.key,
+				Value: line.value,
+				Pos:   Position{Filename: filename, Line: line.line, Column: line.column},
+			})
+		}
+	}
+	return entries, nil
+}
+
+// ParseJSON reads the variables of a JSON object. Nested objects and arrays are flattened by
+// joining the keys and array indexes with separator, so with separator "_" the object
+// {"db": {"hosts": ["a", "b"]}} holds the variables db_hosts_0 and db_hosts_1. Numbers and
+// booleans are kept as written and null becomes an empty value.
+//
+// filename is only used for the positions and errors.
+func ParseJSON(filename string, src []byte, separator string) ([]Entry, error) {
+	entries, err := parseJSON(filename, src, separator)
+	if err != nil {
+		return nil, parseError(filename, err)
+	}
+	return entries, nil
+}
+
+func parseJSON(filename string, src []byte, separator string) ([]Entry, error) {
+	p := &jsonParser{
+		dec:  json.NewDecoder(bytes.NewReader(src)),
+		src:  src,
+		sep:  separator,
+		file: filename,
+	}
+	p.dec.UseNumber()
+
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, errors.New("top level value must be an object")
+	}
+	if err := p.object(""); err != nil {
+		return nil, err
+	}
+	if _, err := p.dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after top level object")
+	}
+	return p.entries, nil
+}
+
+type jsonParser struct {
+	dec     *json.Decoder
+	src     []byte
+	sep     string
+	file    string
+	entries []Entry
+}
+
+// object reads the members of an object up to and including its closing brace.
+func (p *jsonParser) object(prefix string) error {
+	for p.dec.More() {
+		pos := p.position(p.dec.InputOffset())
+		tok, err := p.dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := p.value(joinKey(prefix, tok.(string), p.sep), pos); err != nil {
+			return err
+		}
+	}
+	_, err := p.dec.Token()
+	return err
+}
+
+// array reads the elements of an array up to and including its closing bracket.
+func (p *jsonParser) array(prefix string) error {
+	for i := 0; p.dec.More(); i++ {
+		pos := p.position(p.dec.InputOffset())
+		if err := p.value(joinKey(prefix, strconv.Itoa(i), p.sep), pos); err != nil {
+			return err
+		}
+	}
+	_, err := p.dec.Token()
+	return err
+}
+
+func (p *jsonParser) value(key string, pos Position) error {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	var value string
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return p.object(key)
+		}
+		return p.array(key)
+	case string:
+		value = t
+	case json.Number:
+		value = t.String()
+	case bool:
+		value = strconv.FormatBool(t)
+	case nil:
+		value = ""
+	}
+	p.entries = append(p.entries, Entry{Key: key, Value: value, Pos: pos})
+	return nil
+}
+
+// position returns the position of the first token at or after offset.
+func (p *jsonParser) position(offset int64) Position {
+	i := int(offset)
+	for i < len(p.src) && strings.IndexByte(" \t\r\n,:", p.src[i]) != -1 {
+		i++
+	}
+	line := bytes.Count(p.src[:i], []byte("\n")) + 1
+	column := i - bytes.LastIndexByte(p.src[:i], '\n')
+	return Position{Filename: p.file, Line: line, Column: column}
+}
+
+// ParseYAML reads the variables of a YAML mapping. Nested mappings and sequences are flattened
+// like ParseJSON does, and null becomes an empty value. Scalars are kept as written, so
+// "port: 08080" holds "08080".
+//
+// Only the block style is supported: mappings, sequences, plain and quoted scalars, and literal
+// (|) and folded (>) scalars. Flow style collections, anchors and tags are rejected.
+//
+// filename is only used for the positions and errors.
+func ParseYAML(filename string, src []byte, separator string) ([]Entry, error) {
+	root, err := parseYAML(src)
+	if err != nil {
+		return nil, parseError(filename, err)
+	}
+	if root.kind != yamlMapping {
+		return nil, parseError(filename, errors.New("top level value must be a mapping"))
+	}
+
+	var entries []Entry
+	var flatten func(n *yamlNode, key string)
+	flatten = func(n *yamlNode, key string) {
+		switch n.kind {
+		case yamlMapping:
+			for i, child := range n.children {
+				flatten(child, joinKey(key, n.keys[i], separator))
+			}
+		case yamlSequence:
+			for i, child := range n.children {
+				flatten(child, joinKey(key, strconv.Itoa(i), separator))
+			}
+		default:
+			entries = append(entries, Entry{Key: key, Value: n.value, Pos: yamlPosition(filename, n)})
+		}
+	}
+	flatten(root, "")
+	return entries, nil
+}
+
+// ParseCompose reads the environment block of a service in a docker compose file, which may be
+// a mapping or a list of KEY=value items. Variables without a value, which compose takes from
+// the shell, are skipped.
+//
+// If service is empty, the file must have at most one service with an environment block.
+//
+// filename is only used for the positions and errors.
+func ParseCompose(filename string, src []byte, service string) ([]Entry, error) {
+	root, err := parseYAML(src)
+	if err != nil {
+		return nil, parseError(filename, err)
+	}
+
+	services := yamlChild(root, "services")
+	if services == nil || services.kind != yamlMapping {
+		return nil, parseError(filename, errors.New("no services found"))
+	}
+
+	var names []string
+	var env *yamlNode
+	for i, name := range services.keys {
+		e := yamlChild(services.children[i], "environment")
+		if e == nil || e.null {
+			continue
+		}
+		names = append(names, name)
+		if name == service || service == "" {
+			env = e
+		}
+	}
+
+	switch {
+	case service == "" && len(names) > 1:
+		return nil, parseError(filename, fmt.Errorf("several services have an environment, choose one of %s", strings.Join(names, ", ")))
+	case service != "" && env == nil && yamlChild(services, service) == nil:
+		return nil, parseError(filename, fmt.Errorf("service %q not found", service))
+	case env == nil:
+		return nil, nil
+	}
+
+	var entries []Entry
+	switch env.kind {
+	case yamlMapping:
+		for i, key := range env.keys {
+			value := env.children[i]
+			if value.kind != yamlScalar {
+				return nil, parseError(filename, fmt.Errorf("line %d: value of %s must be a scalar", value.line, key))
+			}
+			if value.null {
+				continue
+			}
+			entries = append(entries, Entry{Key: key, Value: value.value, Pos: yamlPosition(filename, value)})
+		}
+	case yamlSequence:
+		for _, item := range env.children {
+			if item.kind != yamlScalar {
+				return nil, parseError(filename, fmt.Errorf("line %d: environment items must be KEY=value", item.line))
+			}
+			key, value, ok := strings.Cut(item.value, "=")
+			if !ok {
+				continue
+			}
+			entries = append(entries, Entry{Key: key, Value: value, Pos: yamlPosition(filename, item)})
+		}
+	default:
+		return nil, parseError(filename, errors.New("environment must be a mapping or a list"))
+	}
+	return entries, nil
+}
+
+// yamlChild returns the value of key in the mapping n, or nil.
+func yamlChild(n *yamlNode, key string) *yamlNode {
+	if n.kind != yamlMapping {
+		return nil
+	}
+	for i, k := range n.keys {
+		if k == key {
+			return n.children[i]
+		}
+	}
+	return nil
+}
+
+func yamlPosition(filename string, n *yamlNode) Position {
+	return Position{Filename: filename, Line: n.line, Column: n.column}
+}
+
+func joinKey(prefix, key, separator string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + separator + key
+}
+
+func parseError(filename string, err error) error {
+	return fmt.Errorf("goenv: Failed to parse file '%s': %s", filename, err.Error())
+}
@@ -0,0 +1,269 @@
+package goenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnv(t *testing.T) {
+	src := "# comment\nA=1\n  B = \"two\nlines\"\nC=3 # comment\n"
+	expected := []Entry{
+		{Key: "A", Value: "1", Pos: Position{Filename: ".env", Line: 2, Column: 1}},
+		{Key: "B", Value: "two\nlines", Pos: Position{Filename: ".env", Line: 3, Column: 3}},
+		{Key: "C", Value: "3", Pos: Position{Filename: ".env", Line: 5, Column: 1}},
+	}
+
+	got, err := ParseEnv(".env", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseEnv() = failed with error: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseEnv() = got:\n%+v\nexpected:\n%+v", got, expected)
+	}
+
+	parsed, _ := parseInput([]byte(src))
+	if !reflect.DeepEqual(EntryMap(got), parsed) {
+		t.Errorf("ParseEnv() = got %v, expected the same variables as Load %v", EntryMap(got), parsed)
+	}
+
+	_, err = ParseEnv(".env", []byte("A=1\nB\n"))
+	if expected := "goenv: Failed to parse file '.env': line 2: malformed line: Missing '=' in environment variable"; err == nil || err.Error() != expected {
+		t.Errorf("ParseEnv() = got error %v, expected %q", err, expected)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		separator string
+		expected  []Entry
+		err       string
+	}{
+		{
+			name:      "Flat",
+			src:       `{"A": "1", "B": 2.50, "C": true, "D": null}`,
+			separator: "_",
+			expected: []Entry{
+				{Key: "A", Value: "1", Pos: Position{"f", 1, 2}},
+				{Key: "B", Value: "2.50", Pos: Position{"f", 1, 12}},
+				{Key: "C", Value: "true", Pos: Position{"f", 1, 23}},
+				{Key: "D", Value: "", Pos: Position{"f", 1, 34}},
+			},
+		},
+		{
+			name:      "Nested",
+			src:       "{\n  \"DB\": {\n    \"HOST\": \"x\",\n    \"PORTS\": [1, 2]\n  },\n  \"EMPTY\": {}\n}",
+			separator: "__",
+			expected: []Entry{
+				{Key: "DB__HOST", Value: "x", Pos: Position{"f", 3, 5}},
+				{Key: "DB__PORTS__0", Value: "1", Pos: Position{"f", 4, 15}},
+				{Key: "DB__PORTS__1", Value: "2", Pos: Position{"f", 4, 18}},
+			},
+		},
+		{
+			name: "Not an object",
+			src:  `["A"]`,
+			err:  "goenv: Failed to parse file 'f': top level value must be an object",
+		},
+		{
+			name: "Trailing data",
+			src:  `{} {}`,
+			err:  "goenv: Failed to parse file 'f': unexpected data after top level object",
+		},
+		{
+			name: "Invalid",
+			src:  `{"A": }`,
+			err:  "goenv: Failed to parse file 'f': ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSON("f", []byte(tt.src), tt.separator)
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Errorf("ParseJSON() = got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJSON() = failed with error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseJSON() = got:\n%+v\nexpected:\n%+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name:     "Flat",
+			src:      "# config\nA: 1\nB: \"two # words\"\nC: 'it''s' # comment\nD:\nE: ~\nF: plain # comment\nURL: http://x:80/a\n",
+			expected: map[string]string{"A": "1", "B": "two # words", "C": "it's", "D": "", "E": "", "F": "plain", "URL": "http://x:80/a"},
+		},
+		{
+			name:     "Nested",
+			src:      "---\ndb:\n  host: x\n  ports:\n  - 1\n  -   2\nlist:\n  - name: a\n    port: 1\n  - name: b\n",
+			expected: map[string]string{"db_host": "x", "db_ports_0": "1", "db_ports_1": "2", "list_0_name": "a", "list_0_port": "1", "list_1_name": "b"},
+		},
+		{
+			name:     "Block scalars",
+			src:      "KEY: |\n  line one\n\n  # not a comment\n    indented\nFOLDED: >-\n  a\n  b\n\n  c\nNEXT: x\n",
+			expected: map[string]string{"KEY": "line one\n\n# not a comment\n  indented\n", "FOLDED": "a b\nc", "NEXT": "x"},
+		},
+		{
+			name: "Flow style",
+			src:  "A: [1, 2]\n",
+			err:  "goenv: Failed to parse file 'f': line 1: flow style collections are not supported",
+		},
+		{
+			name: "Bad indentation",
+			src:  "A: 1\n  B: 2\n",
+			err:  "goenv: Failed to parse file 'f': line 2: unexpected indentation",
+		},
+		{
+			name: "Missing colon",
+			src:  "A: 1\nB\n",
+			err:  "goenv: Failed to parse file 'f': line 2: expected 'key: value'",
+		},
+		{
+			name: "Top level sequence",
+			src:  "- A\n",
+			err:  "goenv: Failed to parse file 'f': top level value must be a mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseYAML("f", []byte(tt.src), "_")
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("ParseYAML() = got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseYAML() = failed with error: %v", err)
+			}
+			if !reflect.DeepEqual(EntryMap(got), tt.expected) {
+				t.Errorf("ParseYAML() = got:\n%q\nexpected:\n%q", EntryMap(got), tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseYAML_Positions(t *testing.T) {
+	got, err := ParseYAML("f.yml", []byte("a:\n  b: 1\n  c:\n    - x\n"), ".")
+	if err != nil {
+		t.Fatalf("ParseYAML() = failed with error: %v", err)
+	}
+	expected := []Entry{
+		{Key: "a.b", Value: "1", Pos: Position{"f.yml", 2, 3}},
+		{Key: "a.c.0", Value: "x", Pos: Position{"f.yml", 4, 7}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseYAML() = got:\n%+v\nexpected:\n%+v", got, expected)
+	}
+}
+
+func TestParseCompose(t *testing.T) {
+	const compose = `services:
+  web:
+    image: web
+    environment:
+      APP_ENV: production
+      PORT: 8080
+      FROM_SHELL:
+  worker:
+    environment:
+    - QUEUE=jobs
+    - "URL=http://x?a=b"
+    - FROM_SHELL
+  db:
+    image: postgres
+`
+
+	tests := []struct {
+		name     string
+		src      string
+		service  string
+		expected []Entry
+		err      string
+	}{
+		{
+			name:    "Mapping",
+			src:     compose,
+			service: "web",
+			expected: []Entry{
+				{Key: "APP_ENV", Value: "production", Pos: Position{"c", 5, 7}},
+				{Key: "PORT", Value: "8080", Pos: Position{"c", 6, 7}},
+			},
+		},
+		{
+			name:    "List",
+			src:     compose,
+			service: "worker",
+			expected: []Entry{
+				{Key: "QUEUE", Value: "jobs", Pos: Position{"c", 10, 7}},
+				{Key: "URL", Value: "http://x?a=b", Pos: Position{"c", 11, 7}},
+			},
+		},
+		{
+			name:     "Service without environment",
+			src:      compose,
+			service:  "db",
+			expected: nil,
+		},
+		{
+			name:    "Single service",
+			src:     "services:\n  app:\n    environment:\n      - A=1\n",
+			service: "",
+			expected: []Entry{
+				{Key: "A", Value: "1", Pos: Position{"c", 4, 9}},
+			},
+		},
+		{
+			name: "Several services",
+			src:  compose,
+			err:  "goenv: Failed to parse file 'c': several services have an environment, choose one of web, worker",
+		},
+		{
+			name:    "Unknown service",
+			src:     compose,
+			service: "cache",
+			err:     "goenv: Failed to parse file 'c': service \"cache\" not found",
+		},
+		{
+			name: "No services",
+			src:  "version: '3'\n",
+			err:  "goenv: Failed to parse file 'c': no services found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCompose("c", []byte(tt.src), tt.service)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("ParseCompose() = got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCompose() = failed with error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseCompose() = got:\n%+v\nexpected:\n%+v", got, tt.expected)
+			}
+		})
+	}
+}
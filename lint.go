@@ -0,0 +1,235 @@
+package goenv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Position is a location in a dotenv file. Line and Column start at 1, and Column counts bytes.
+type Position struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+// String returns the position as "file:line:col", leaving out the parts that are unknown.
+func (p Position) String() string {
+	s := p.Filename
+	if p.Line > 0 {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%d", p.Line)
+		if p.Column > 0 {
+			s += fmt.Sprintf(":%d", p.Column)
+		}
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
+
+// Severity tells whether a Problem makes a file unusable or only looks suspicious.
+type Severity int
+
+const (
+	// SeverityError marks a file that fails to load or loads differently than it reads.
+	SeverityError Severity = iota
+	// SeverityWarning marks content that loads, but is likely a mistake.
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Problem is an issue Lint found in a dotenv file.
+type Problem struct {
+	Pos      Position
+	Severity Severity
+	Message  string
+}
+
+// String returns the problem as "file:line:col: message".
+func (p Problem) String() string {
+	return p.Pos.String() + ": " + p.Message
+}
+
+// Lint checks the content of a dotenv file against stricter rules than Load applies and returns every
+// problem it finds, in the order they appear. filename is only used for the positions.
+//
+// Errors are reported for malformed lines, invalid keys, duplicate keys, unterminated quotes and empty
+// unquoted values followed by more content, which Load reads as the value. Warnings are reported for keys
+// that differ only in case, keys that are not valid shell names, trailing whitespace, unquoted '#'
+// characters that are kept in the value, text after a closing quote and single quoted values.
+func Lint(filename string, src []byte) []Problem {
+	l := &linter{filename: filename, keys: make(map[string]int), folded: make(map[string]string)}
+	l.lint(bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n")))
+	return l.problems
+}
+
+type linter struct {
+	filename string
+	problems []Problem
+	// keys maps every key to the line it was first defined on.
+	keys map[string]int
+	// folded maps every lower case key to its first spelling.
+	folded map[string]string
+}
+
+func (l *linter) report(line, col int, severity Severity, format string, args ...any) {
+	l.problems = append(l.problems, Problem{
+		Pos:      Position{Filename: l.filename, Line: line, Column: col},
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lint(src []byte) {
+	lines := strings.Split(string(src), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimRight(lines[i], " \t")
+		trailing := len(line) != len(lines[i])
+
+		start := len(line) - len(strings.TrimLeft(line, " \t"))
+		eq := strings.IndexByte(line, '=')
+		switch {
+		case start == len(line) || line[start] == '#':
+		case eq == -1:
+			l.report(lineNo, start+1, SeverityError, "malformed line: missing '='")
+		default:
+			key := strings.TrimSpace(line[:eq])
+			l.checkKey(key, lineNo, start+1)
+
+			valueStart := eq + 1
+			for valueStart < len(line) && isSpace(line[valueStart]) {
+				valueStart++
+			}
+			value := line[valueStart:]
+
+			switch {
+			case value == "":
+				if hasContent(lines[i+1:]) {
+					l.report(lineNo, valueStart+1, SeverityError, "empty value of %s must be quoted as \"\", otherwise the next line is read as its value", key)
+				}
+			case value[0] == '"':
+				end, ok := l.checkQuoted(lines, i, valueStart)
+				if !ok {
+					return
+				}
+				if end > i {
+					// whitespace at the end of the opening line is part of the value
+					i = end
+					lineNo = i + 1
+					line = strings.TrimRight(lines[i], " \t")
+					trailing = len(line) != len(lines[i])
+				}
+			default:
+				l.checkUnquoted(value, lineNo, valueStart+1)
+			}
+		}
+
+		if trailing {
+			l.report(lineNo, len(line)+1, SeverityWarning, "trailing whitespace")
+		}
+	}
+}
+
+func (l *linter) checkKey(key string, line, col int) {
+	switch {
+	case key == "":
+		l.report(line, col, SeverityError, "malformed line: missing key before '='")
+		return
+	case strings.ContainsAny(key, " \t"):
+		l.report(line, col, SeverityError, "malformed line: key %q contains whitespace", key)
+		return
+	case !IsShellName(key):
+		l.report(line, col, SeverityWarning, "key %q is not a valid shell variable name", key)
+	}
+
+	if first, ok := l.keys[key]; ok {
+		l.report(line, col, SeverityError, "duplicate key %s, first defined on line %d", key, first)
+		return
+	}
+	l.keys[key] = line
+
+	lower := strings.ToLower(key)
+	if other, ok := l.folded[lower]; ok {
+		l.report(line, col, SeverityWarning, "key %s differs only in case from %s on line %d", key, other, l.keys[other])
+		return
+	}
+	l.folded[lower] = key
+}
+
+// checkQuoted checks the quoted value starting at column start of lines[i] and returns the index of the
+// line it ends on. ok is false if the quote is never closed.
+func (l *linter) checkQuoted(lines []string, i, start int) (end int, ok bool) {
+	offset := start + 1
+	for end = i; end < len(lines); end++ {
+		line := lines[end]
+		q := strings.IndexByte(line[offset:], '"')
+		if q == -1 {
+			offset = 0
+			continue
+		}
+
+		after := offset + q + 1
+		for after < len(line) && isSpace(line[after]) {
+			after++
+		}
+		if after < len(line) && line[after] != '#' {
+			l.report(end+1, after+1, SeverityWarning, "text after closing quote is ignored")
+		}
+		return end, true
+	}
+
+	l.report(i+1, start+1, SeverityError, "unterminated quoted value: missing end quote '\"'")
+	return len(lines) - 1, false
+}
+
+func (l *linter) checkUnquoted(value string, line, col int) {
+	if value[0] == '\'' {
+		l.report(line, col, SeverityWarning, "single quotes are kept in the value, use double quotes instead")
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] != '#' {
+			continue
+		}
+		if i > 0 && isSpace(value[i-1]) {
+			// the rest of the line is a comment
+			return
+		}
+		l.report(line, col+i, SeverityWarning, "unquoted '#' is kept in the value, quote the value to make this explicit")
+		return
+	}
+}
+
+// hasContent reports whether any of lines contains more than whitespace.
+func hasContent(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsShellName reports whether key is a valid POSIX shell variable name: letters, digits and
+// underscores, not starting with a digit. Other keys can be loaded, but not exported by a shell.
+func IsShellName(key string) bool {
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return key != ""
+}
@@ -0,0 +1,157 @@
+package goenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name:     "Clean file",
+			src:      "# comment\nA=1\n\nB=\"two words\" # comment\nC=\"\"\nD=\"multi\nline\"\nE=x # comment\n",
+			expected: nil,
+		},
+		{
+			name:     "Missing delimiter",
+			src:      "A=1\n  B\n",
+			expected: []string{"f:2:3: malformed line: missing '='"},
+		},
+		{
+			name:     "Missing key",
+			src:      "=1\n",
+			expected: []string{"f:1:1: malformed line: missing key before '='"},
+		},
+		{
+			name:     "Whitespace in key",
+			src:      "export A=1\n",
+			expected: []string{`f:1:1: malformed line: key "export A" contains whitespace`},
+		},
+		{
+			name:     "Invalid shell name",
+			src:      "app.name=1\n",
+			expected: []string{`f:1:1: key "app.name" is not a valid shell variable name`},
+		},
+		{
+			name:     "Duplicate key",
+			src:      "A=1\nB=2\nA=3\n",
+			expected: []string{"f:3:1: duplicate key A, first defined on line 1"},
+		},
+		{
+			name:     "Keys differ in case",
+			src:      "Api_Key=1\nAPI_KEY=2\n",
+			expected: []string{"f:2:1: key API_KEY differs only in case from Api_Key on line 1"},
+		},
+		{
+			name:     "Unterminated quote",
+			src:      "A=1\nB=\"open\nC=3\n",
+			expected: []string{"f:2:3: unterminated quoted value: missing end quote '\"'"},
+		},
+		{
+			name:     "Empty value before content",
+			src:      "A=\nB=2\n",
+			expected: []string{"f:1:3: empty value of A must be quoted as \"\", otherwise the next line is read as its value"},
+		},
+		{
+			name:     "Empty value at end of file",
+			src:      "A=1\nB=\n\n",
+			expected: nil,
+		},
+		{
+			name:     "Trailing whitespace",
+			src:      "A=1  \n# note\t\n",
+			expected: []string{"f:1:4: trailing whitespace", "f:2:7: trailing whitespace"},
+		},
+		{
+			name:     "Trailing whitespace inside quotes",
+			src:      "A=\"one  \ntwo\" \n",
+			expected: []string{"f:2:5: trailing whitespace"},
+		},
+		{
+			name:     "Unquoted hash",
+			src:      "PASSWORD=abc#123\nCOLOR=#fff\n",
+			expected: []string{"f:1:13: unquoted '#' is kept in the value, quote the value to make this explicit", "f:2:7: unquoted '#' is kept in the value, quote the value to make this explicit"},
+		},
+		{
+			name:     "Text after closing quote",
+			src:      "A=\"one\" two\n",
+			expected: []string{"f:1:9: text after closing quote is ignored"},
+		},
+		{
+			name:     "Single quotes",
+			src:      "A='one'\n",
+			expected: []string{"f:1:3: single quotes are kept in the value, use double quotes instead"},
+		},
+		{
+			name:     "Windows line endings",
+			src:      "A=1\r\nB=2\r\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range Lint("f", []byte(tt.src)) {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Lint() = got:\n%q\nexpected:\n%q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLint_Severity(t *testing.T) {
+	problems := Lint("f", []byte("A=1\nA=2 \n"))
+	if len(problems) != 2 {
+		t.Fatalf("Lint() = got %d problems, expected 2", len(problems))
+	}
+	if problems[0].Severity != SeverityError || problems[1].Severity != SeverityWarning {
+		t.Errorf("Lint() = got severities %s and %s, expected error and warning", problems[0].Severity, problems[1].Severity)
+	}
+}
+
+func TestPosition_String(t *testing.T) {
+	tests := []struct {
+		pos      Position
+		expected string
+	}{
+		{Position{Filename: ".env", Line: 3, Column: 7}, ".env:3:7"},
+		{Position{Filename: ".env", Line: 3}, ".env:3"},
+		{Position{Filename: ".env"}, ".env"},
+		{Position{Line: 2, Column: 1}, "2:1"},
+		{Position{}, "-"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pos.String(); got != tt.expected {
+			t.Errorf("Position.String() = got %q, expected %q", got, tt.expected)
+		}
+	}
+}
+
+func TestIsShellName(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{"DB_HOST", true},
+		{"_private", true},
+		{"A1", true},
+		{"1A", false},
+		{"DB-HOST", false},
+		{"DB.HOST", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsShellName(tt.key); got != tt.expected {
+			t.Errorf("IsShellName(%q) = got %v, expected %v", tt.key, got, tt.expected)
+		}
+	}
+}
@@ -4,50 +4,129 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"maps"
 	"os"
-	"strings"
+	"slices"
 )
 
-func loadFiles(filenames []string) error {
+func loadFiles(filenames []string, override bool) error {
+	vars, err := resolveFiles(filenames, os.LookupEnv, override)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range vars {
+		os.Setenv(key, value)
+	}
+
+	return nil
+}
+
+// resolveFiles returns the variables of files that should be set in an environment, where lookup reports
+// the variables already in it. Every variable of the first file is set, while the remaining files only set
+// variables not already in the environment, unless override is true. A variable in several files is always
+// taken from the first of them.
+func resolveFiles(filenames []string, lookup func(string) (string, bool), override bool) (map[string]string, error) {
 	if len(filenames) == 0 {
-		filenames = append(filenames, ".env")
+		filenames = append(filenames, defaultFile())
 	}
 
+	vars := make(map[string]string)
 	for i, filename := range filenames {
-		if err := loadFile(filename, i == 0); err != nil {
-			return fmt.Errorf("goenv: Failed to load file '%s': %s", filename, err.Error())
+		fileMap, err := loadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("goenv: Failed to load file '%s': %s", filename, err.Error())
+		}
+
+		for key, value := range fileMap {
+			if _, loaded := vars[key]; loaded {
+				continue
+			}
+			if _, exists := lookup(key); exists && i > 0 && !override {
+				continue
+			}
+			vars[key] = value
 		}
 	}
 
-	return nil
+	return vars, nil
 }
 
-func loadFile(filename string, overload bool) error {
+// defaultFile returns ".env", or ".env.enc" if only the encrypted file exists.
+func defaultFile() string {
+	if _, err := os.Stat(".env"); os.IsNotExist(err) {
+		if _, err := os.Stat(".env.enc"); err == nil {
+			return ".env.enc"
+		}
+	}
+	return ".env"
+}
+
+func loadFile(filename string) (map[string]string, error) {
 	src, err := readFile(filename)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fileMap, err := parseInput(src)
-	if err != nil {
-		return err
+	return decodeFile(src)
+}
+
+// decodeFile parses the content of a file, decrypting it if it is encrypted as a whole,
+// and decrypting every encrypted value in it.
+func decodeFile(src []byte) (map[string]string, error) {
+	var c *Cipher
+	var err error
+	if IsEncrypted(string(src)) {
+		if c, err = CipherFromEnv(); err != nil {
+			return nil, err
+		}
+		if src, err = c.Decrypt(string(src)); err != nil {
+			return nil, err
+		}
 	}
 
-	curEnv := os.Environ()
-	curEnvMap := make(map[string]bool)
-	for _, val := range curEnv {
-		curKey := strings.Split(val, "=")[0]
-		curEnvMap[curKey] = true
+	vars, err := parseInput(src)
+	if err != nil {
+		return nil, err
 	}
 
-	for key, value := range fileMap {
-		exists := curEnvMap[key]
-		if !exists || overload {
-			os.Setenv(key, value)
+	for _, key := range fileKeys(src, vars) {
+		value := vars[key]
+		if !IsEncrypted(value) {
+			continue
+		}
+		if c == nil {
+			if c, err = CipherFromEnv(); err != nil {
+				return nil, fmt.Errorf("%s: %s", key, err.Error())
+			}
 		}
+		plaintext, err := c.Decrypt(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err.Error())
+		}
+		vars[key] = string(plaintext)
 	}
 
-	return nil
+	return vars, nil
+}
+
+// fileKeys returns the keys of vars, the variables parsed from src, in the order they are defined in src,
+// so errors always name the first key that fails. Keys that only parseInput accepts are sorted instead.
+func fileKeys(src []byte, vars map[string]string) []string {
+	lines, err := scanLines(bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n")))
+	if err != nil {
+		return slices.Sorted(maps.Keys(vars))
+	}
+
+	keys := make([]string, 0, len(vars))
+	seen := make(map[string]bool, len(vars))
+	for _, line := range lines {
+		if line.entry && !seen[line.key] {
+			seen[line.key] = true
+			keys = append(keys, line.key)
+		}
+	}
+	return keys
 }
 
 func readFile(filename string) ([]byte, error) {
@@ -189,3 +189,130 @@ func TestLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected map[string]string
+	}{
+		{
+			name:  "Read single file",
+			files: []string{"testdata/.env.ci"},
+			expected: map[string]string{
+				"APP_ENV":        "ci",
+				"DEBUG":          "false",
+				"DB_HOST":        "localhost",
+				"DB_PORT":        "5432",
+				"DB_USER":        "ci_runner",
+				"RUN_E2E":        "true",
+				"PARALLEL_JOBS":  "4",
+				"GIT_COMMIT_SHA": "abcdef123456",
+				"CI_PIPELINE_ID": "78910",
+			},
+		},
+		{
+			name:  "Read two files",
+			files: []string{"testdata/.env.development", "testdata/.env.ci"},
+			expected: map[string]string{
+				"APP_ENV":             "development",
+				"DEBUG":               "true",
+				"API_URL":             "http://localhost:3000/api",
+				"DB_HOST":             "localhost",
+				"DB_PORT":             "5432",
+				"DB_USER":             "dev_user",
+				"FEATURE_FLAG_NEW_UI": "true",
+				"CACHE_TTL":           "60",
+				"LOG_LEVEL":           "debug",
+				"RUN_E2E":             "true",
+				"PARALLEL_JOBS":       "4",
+				"GIT_COMMIT_SHA":      "abcdef123456",
+				"CI_PIPELINE_ID":      "78910",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(tt.files...)
+			if err != nil {
+				t.Fatalf("Read() = failed with error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Read() = got:\n%v\nexpected:\n%v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	env := map[string]string{"APP_ENV": "local", "RUN_E2E": "false", "HOME": "/root"}
+
+	tests := []struct {
+		name     string
+		override bool
+		files    []string
+		expected map[string]string
+	}{
+		{
+			name:  "First file overrides",
+			files: []string{"testdata/.env.ci"},
+			expected: map[string]string{
+				"APP_ENV": "ci",
+				"RUN_E2E": "true",
+				"HOME":    "/root",
+			},
+		},
+		{
+			name:  "Later files keep environment",
+			files: []string{"testdata/.env.development", "testdata/.env.ci"},
+			expected: map[string]string{
+				"APP_ENV": "development",
+				"RUN_E2E": "false",
+				"HOME":    "/root",
+			},
+		},
+		{
+			name:     "Override later files",
+			override: true,
+			files:    []string{"testdata/.env.development", "testdata/.env.ci"},
+			expected: map[string]string{
+				"APP_ENV": "development",
+				"RUN_E2E": "true",
+				"HOME":    "/root",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge(env, tt.override, tt.files...)
+			if err != nil {
+				t.Fatalf("Merge() = failed with error: %v", err)
+			}
+			for key, want := range tt.expected {
+				if got[key] != want {
+					t.Errorf("Merge() = got %s=%q, expected %q", key, got[key], want)
+				}
+			}
+			if env["APP_ENV"] != "local" {
+				t.Errorf("Merge() = modified the given environment")
+			}
+		})
+	}
+}
+
+func TestOverload(t *testing.T) {
+	t.Setenv("PARALLEL_JOBS", "1")
+	t.Setenv("CACHE_TTL", "1")
+
+	if err := Overload("testdata/.env.development", "testdata/.env.ci"); err != nil {
+		t.Fatalf("Overload() = failed with error: %v", err)
+	}
+	if got := os.Getenv("PARALLEL_JOBS"); got != "4" {
+		t.Errorf("Overload() = got PARALLEL_JOBS=%q, expected %q", got, "4")
+	}
+	if got := os.Getenv("CACHE_TTL"); got != "60" {
+		t.Errorf("Overload() = got CACHE_TTL=%q, expected %q", got, "60")
+	}
+}
@@ -0,0 +1,180 @@
+package goenv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalStruct is the inverse of Struct. It returns the environment variables that the struct v
+// would be populated from, keyed by their full keys and formatted so that Struct reads back the
+// same values, e.g. with WithEnvironment:
+//
+//	env, err := goenv.MarshalStruct(cfg)
+//	if err != nil {
+//		return err
+//	}
+//	var copy Config
+//	err = goenv.Struct(&copy, goenv.WithEnvironment(env))
+//
+// All fields are included, including zero values and fields tagged sensitive. Fields with the
+// file option are skipped, as their variables hold the path of a file rather than the value.
+//
+// Empty values do not round-trip, as Struct treats an empty variable as unset: a field that is
+// formatted as "", such as an empty string or a nil *url.URL, is read back as its default if it has
+// one, and fails if it is required. Struct also checks the validation rules that MarshalStruct ignores.
+//
+// v may be a struct or a pointer to a struct. The options are the same as for Struct.
+func MarshalStruct(v any, opts ...Option) (map[string]string, error) {
+	val, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+	var errs ValidationErrors
+	walker := structWalker{
+		opts: newOptions(opts),
+		field: func(f envField, err error) {
+			if err != nil {
+				errs = append(errs, &FieldError{Field: f.path, Err: err})
+				return
+			}
+			if f.tag.file {
+				return
+			}
+
+			value, err := formatFieldValue(f.value, f.tag)
+			if err != nil {
+				errs = append(errs, &FieldError{Field: f.path, Key: f.key, Err: err})
+				return
+			}
+			env[f.key] = value
+		},
+	}
+	walker.walk(val, "", walker.opts.prefix)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return env, nil
+}
+
+// Environ returns env as a sorted list of "KEY=value" strings, the form used by os.Environ
+// and exec.Cmd.Env. To extend the environment of the current process, append it to os.Environ():
+//
+//	cmd.Env = append(os.Environ(), goenv.Environ(env)...)
+func Environ(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for key, value := range env {
+		list = append(list, key+"="+value)
+	}
+	slices.Sort(list)
+	return list
+}
+
+// Write writes env to w in the dotenv format read by Load, as one KEY=value line per variable
+// sorted by key. Values are quoted only when necessary.
+//
+// Write fails for keys that cannot be read back, and for values that need quotes but contain
+// a double quote, as the format has no escape sequences.
+func Write(w io.Writer, env map[string]string) error {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		if key == "" || key != strings.TrimSpace(key) || strings.ContainsAny(key, "=\n") || key[0] == '#' {
+			return fmt.Errorf("goenv: cannot write key %q", key)
+		}
+		value, err := formatValue(env[key])
+		if err != nil {
+			return fmt.Errorf("goenv: cannot write %s: %s", key, err.Error())
+		}
+		buf.WriteString(key + "=" + value + "\n")
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// formatFieldValue formats the value of field so that setFieldValue parses it back to the same value.
+func formatFieldValue(field reflect.Value, tag tagConfig) (string, error) {
+	switch v := field.Interface().(type) {
+	case string:
+		return v, nil
+	case int, int8, int16, int32, int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return strconv.FormatUint(field.Uint(), 10), nil
+	case float32:
+		return strconv.FormatFloat(field.Float(), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Duration:
+		return v.String(), nil
+	case ByteSize:
+		return v.String(), nil
+	case time.Time:
+		return formatTimeValue(v, tag.layout, tag.location), nil
+	case url.URL:
+		return v.String(), nil
+	case *url.URL:
+		if v == nil {
+			return "", nil
+		}
+		return v.String(), nil
+	case net.IP:
+		if v == nil {
+			return "", nil
+		}
+		return v.String(), nil
+	case netip.Addr:
+		if !v.IsValid() {
+			return "", nil
+		}
+		return v.String(), nil
+	case netip.Prefix:
+		if !v.IsValid() {
+			return "", nil
+		}
+		return v.String(), nil
+	case *regexp.Regexp:
+		if v == nil {
+			return "", nil
+		}
+		return v.String(), nil
+	case []byte:
+		if v == nil {
+			return "", nil
+		}
+		return "base64:" + base64.StdEncoding.EncodeToString(v), nil
+	case time.Location:
+		return v.String(), nil
+	case *time.Location:
+		if v == nil {
+			return "", nil
+		}
+		return v.String(), nil
+	case os.FileMode:
+		return fmt.Sprintf("%#o", uint32(v)), nil
+	}
+
+	return "", fmt.Errorf("unsupported field type %s", field.Kind())
+}
@@ -0,0 +1,172 @@
+package goenv
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testMarshalConfig struct {
+	Name      string        `goenv:"NAME,required"`
+	Port      int           `goenv:"PORT,default=8080"`
+	Workers   uint8         `goenv:"WORKERS"`
+	Ratio     float32       `goenv:"RATIO"`
+	Precision float64       `goenv:"PRECISION"`
+	Debug     bool          `goenv:"DEBUG"`
+	Timeout   time.Duration `goenv:"TIMEOUT"`
+	Started   time.Time     `goenv:"STARTED"`
+	Password  string        `goenv:"PASSWORD,sensitive"`
+	Cert      string        `goenv:"CERT,file"`
+	Untagged  string
+	Database  struct {
+		Host string `goenv:"HOST"`
+	} `envPrefix:"DB_"`
+}
+
+func TestMarshalStruct(t *testing.T) {
+	cfg := testMarshalConfig{
+		Name:      "myapp",
+		Port:      9090,
+		Workers:   4,
+		Ratio:     0.1,
+		Precision: 2.718281828,
+		Debug:     true,
+		Timeout:   90 * time.Second,
+		Started:   time.Date(2025, 6, 13, 10, 30, 0, 500, time.UTC),
+		Password:  "s3cret",
+		Cert:      "-----BEGIN CERTIFICATE-----",
+		Untagged:  "untagged",
+	}
+	cfg.Database.Host = "db.internal"
+
+	env, err := MarshalStruct(&cfg, WithPrefix("APP_"))
+	if err != nil {
+		t.Fatalf("MarshalStruct() error = %v", err)
+	}
+
+	want := map[string]string{
+		"APP_NAME":      "myapp",
+		"APP_PORT":      "9090",
+		"APP_WORKERS":   "4",
+		"APP_RATIO":     "0.1",
+		"APP_PRECISION": "2.718281828",
+		"APP_DEBUG":     "true",
+		"APP_TIMEOUT":   "1m30s",
+		"APP_STARTED":   "2025-06-13T10:30:00.0000005Z",
+		"APP_PASSWORD":  "s3cret",
+		"APP_DB_HOST":   "db.internal",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("MarshalStruct() = %v, want %v", env, want)
+	}
+
+	var got testMarshalConfig
+	if err := Struct(&got, WithPrefix("APP_"), WithEnvironment(env)); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+	cfg.Cert = ""
+	cfg.Untagged = ""
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("Struct(MarshalStruct()) = %+v, want %+v", got, cfg)
+	}
+}
+
+func TestMarshalStructEmptyValues(t *testing.T) {
+	type config struct {
+		Name  string `goenv:"PX_NAME,default=svc"`
+		Token string `goenv:"PX_TOKEN,required"`
+		Port  int    `goenv:"PX_PORT,default=8080"`
+	}
+
+	env, err := MarshalStruct(config{Token: "t0ken"})
+	if err != nil {
+		t.Fatalf("MarshalStruct() error = %v", err)
+	}
+	if want := map[string]string{"PX_NAME": "", "PX_TOKEN": "t0ken", "PX_PORT": "0"}; !reflect.DeepEqual(env, want) {
+		t.Errorf("MarshalStruct() = %v, want %v", env, want)
+	}
+
+	// the empty name is read as unset and gets its default, while a zero port is not empty
+	var got config
+	if err := Struct(&got, WithEnvironment(env)); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+	if want := (config{Name: "svc", Token: "t0ken"}); got != want {
+		t.Errorf("Struct(MarshalStruct()) = %+v, want %+v", got, want)
+	}
+
+	env, err = MarshalStruct(config{Name: "svc"})
+	if err != nil {
+		t.Fatalf("MarshalStruct() error = %v", err)
+	}
+	if err := Struct(&got, WithEnvironment(env)); !errors.Is(err, ErrMissingRequired) {
+		t.Errorf("Struct() error = %v, want %v for an empty required field", err, ErrMissingRequired)
+	}
+}
+
+func TestMarshalStructUnsupported(t *testing.T) {
+	cfg := struct {
+		Values []string `goenv:"VALUES"`
+	}{}
+	if _, err := MarshalStruct(cfg); err == nil {
+		t.Errorf("MarshalStruct() error = nil, want error for unsupported type")
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	got := Environ(map[string]string{"B": "2", "A": "1", "C": "a=b"})
+	want := []string{"A=1", "B=2", "C=a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Environ() = %v, want %v", got, want)
+	}
+}
+
+func TestStructWithEnvironment(t *testing.T) {
+	secret := writeTestFile(t, "password", "s3cret\n", 0o600)
+	env := map[string]string{
+		"NAME":          "from-map",
+		"PASSWORD_FILE": secret,
+	}
+	t.Setenv("NAME", "from-process")
+
+	var cfg testMarshalConfig
+	if err := Struct(&cfg, WithEnvironment(env)); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+	if cfg.Name != "from-map" {
+		t.Errorf("Name = %v, want %v", cfg.Name, "from-map")
+	}
+	if cfg.Password != "s3cret" {
+		t.Errorf("Password = %v, want %v", cfg.Password, "s3cret")
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %v, want %v", cfg.Port, 8080)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	env := map[string]string{"B": "two words", "A": "", "C": " padded", "D": "multi\nline"}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, env); err != nil {
+		t.Fatalf("Write() = failed with error: %v", err)
+	}
+	expected := "A=\"\"\nB=two words\nC=\" padded\"\nD=\"multi\nline\"\n"
+	if buf.String() != expected {
+		t.Errorf("Write() = got %q, expected %q", buf.String(), expected)
+	}
+
+	parsed, err := parseInput(buf.Bytes())
+	if err != nil || !reflect.DeepEqual(parsed, env) {
+		t.Errorf("Write() = read back %v, %v, expected %v", parsed, err, env)
+	}
+
+	for _, env := range []map[string]string{{"A": ` "quoted"`}, {"A=B": "1"}, {"#A": "1"}} {
+		if err := Write(&buf, env); err == nil || !strings.HasPrefix(err.Error(), "goenv: cannot write") {
+			t.Errorf("Write(%v) = got error %v, expected cannot write", env, err)
+		}
+	}
+}
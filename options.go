@@ -0,0 +1,132 @@
+package goenv
+
+import (
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Option configures how Struct reads environment variables.
+type Option func(*options)
+
+type options struct {
+	prefix      string
+	autoKeys    bool
+	maxFileSize int64
+	filePerm    os.FileMode
+	env         map[string]string
+	logger      *slog.Logger
+	boolWords   *BoolWords
+	// pollInterval makes a Watcher poll its files instead of being notified of changes.
+	pollInterval time.Duration
+}
+
+// defaultOptions are used by the getters, which take no options.
+var defaultOptions = newOptions(nil)
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		maxFileSize: defaultMaxFileSize,
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithPrefix prepends prefix to the key of every field, including fields of nested structs.
+//
+// It composes with envPrefix tags, so a field tagged `goenv:"HOST"` inside a struct field tagged
+// `envPrefix:"DB_"` is read from "MYAPP_DB_HOST" when WithPrefix("MYAPP_") is used.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithAutoKeys derives a key for every field without an explicit key from its name,
+// e.g. ReadTimeout is read from "READ_TIMEOUT" and APIKey from "API_KEY".
+//
+// Nested struct fields without an envPrefix tag add their own name to the prefix, so
+// Database.Port is read from "DATABASE_PORT". Embedded structs do not add a prefix.
+// Options can still be given without a key, as in `goenv:",required"`, and fields
+// tagged `goenv:"-"` are skipped.
+func WithAutoKeys() Option {
+	return func(o *options) {
+		o.autoKeys = true
+	}
+}
+
+// WithMaxFileSize limits the size of files read through KEY_FILE variables and the file tag option.
+// Larger files are reported as an error. The default limit is 1 MiB.
+func WithMaxFileSize(n int64) Option {
+	return func(o *options) {
+		o.maxFileSize = n
+	}
+}
+
+// WithFilePermissions rejects files read through KEY_FILE variables and the file tag option,
+// whose permission bits are not a subset of perm. For example, WithFilePermissions(0o600) rejects
+// secret files that are readable by group or others. By default permissions are not checked.
+func WithFilePermissions(perm os.FileMode) Option {
+	return func(o *options) {
+		o.filePerm = perm
+	}
+}
+
+// WithEnvironment makes Struct read variables, including KEY_FILE variables, from env instead of
+// the process environment. The unset tag option has no effect on env.
+func WithEnvironment(env map[string]string) Option {
+	return func(o *options) {
+		o.env = env
+	}
+}
+
+// WithLogger sets the logger that deprecation warnings are written to when a field is read from
+// one of its aliases, and that a Watcher without OnError callbacks reports failed reloads to.
+// The default is slog.Default(), and a nil logger disables the warnings.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// warnDeprecated logs that f was read from its deprecated alias.
+func (o *options) warnDeprecated(f envField, alias string) {
+	if o.logger == nil {
+		return
+	}
+	o.logger.Warn("goenv: deprecated environment variable", "key", alias, "use", f.key, "field", f.path)
+}
+
+// WithBoolWords sets the words, besides those accepted by strconv.ParseBool, that bool fields accept.
+// The default is DefaultBoolWords.
+func WithBoolWords(words BoolWords) Option {
+	return func(o *options) {
+		o.boolWords = &words
+	}
+}
+
+// WithStrictBool makes bool fields only accept the values strconv.ParseBool does,
+// such as "true", "false", "1" and "0".
+func WithStrictBool() Option {
+	return WithBoolWords(BoolWords{})
+}
+
+// parseBool parses s with the configured bool words.
+func (o *options) parseBool(s string) (bool, error) {
+	if o.boolWords != nil {
+		return o.boolWords.Parse(s)
+	}
+	return DefaultBoolWords.Parse(s)
+}
+
+// WithPollInterval makes a Watcher check its files for changes every d, instead of being notified
+// by the operating system. Polling also works for file systems that do not report changes, such as
+// network file systems. It has no effect on the other functions.
+func WithPollInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = d
+	}
+}
@@ -28,7 +28,7 @@ func parseInput(src []byte) (map[string]string, error) {
 			return nil, fmt.Errorf("malformed line: %s", err.Error())
 		}
 
-		value, rest, err := findValue(rest)
+		value, _, rest, err := findValue(rest)
 		if err != nil {
 			return nil, fmt.Errorf("malformed value: %s", err.Error())
 		}
@@ -54,7 +54,9 @@ func findKey(src []byte) (string, []byte, error) {
 	return strings.TrimSpace(key), rest, nil
 }
 
-func findValue(src []byte) (string, []byte, error) {
+// findValue returns the value at the start of src, the comment that follows it on the same line, if any,
+// and the source after the line.
+func findValue(src []byte) (string, string, []byte, error) {
 	src, isQuoted := findValueStart(src)
 	if isQuoted {
 		return readStringValue(src)
@@ -86,28 +88,39 @@ func findValue(src []byte) (string, []byte, error) {
 		}
 	}
 
-	return strings.TrimSpace(string(value[:valLength])), rest, nil
+	comment := strings.TrimSpace(string(value[valLength:]))
+	return strings.TrimSpace(string(value[:valLength])), comment, rest, nil
 }
 
-func readStringValue(src []byte) (string, []byte, error) {
+func readStringValue(src []byte) (string, string, []byte, error) {
 	endQuoteIndex := bytes.IndexFunc(src, func(r rune) bool {
 		return r == '"'
 	})
 	if endQuoteIndex == -1 {
-		return "", nil, missingEndQuote
+		return "", "", nil, missingEndQuote
 	}
 
 	value := string(src[:endQuoteIndex])
 	rest := src[endQuoteIndex+1:]
 
+	line := rest
 	delimIndex := bytes.IndexFunc(rest, func(r rune) bool {
 		return r == '\n'
 	})
 	if delimIndex != -1 {
+		line = rest[:delimIndex]
 		rest = rest[delimIndex+1:]
 	}
 
-	return value, rest, nil
+	// text after the closing quote is ignored, unless it is a comment
+	comment := strings.TrimSpace(string(line))
+	if !strings.HasPrefix(comment, "#") {
+		comment = ""
+	} else if delimIndex == -1 {
+		rest = []byte{}
+	}
+
+	return value, comment, rest, nil
 }
 
 func findValueStart(src []byte) ([]byte, bool) {
@@ -163,3 +176,25 @@ func isSpace(r byte) bool {
 	}
 	return false
 }
+
+var unquotableValue error = fmt.Errorf("Value needs quotes but contains '\"'")
+
+// formatValue returns value in a form that parseInput reads back as value,
+// quoting it only when necessary.
+func formatValue(value string) (string, error) {
+	needsQuotes := value == "" ||
+		strings.TrimSpace(value) != value ||
+		strings.HasPrefix(value, `"`) ||
+		strings.Contains(value, "\n")
+	for i := 1; i < len(value) && !needsQuotes; i++ {
+		needsQuotes = value[i] == '#' && isSpace(value[i-1])
+	}
+
+	if !needsQuotes {
+		return value, nil
+	}
+	if strings.Contains(value, `"`) {
+		return "", unquotableValue
+	}
+	return `"` + value + `"`, nil
+}
@@ -170,3 +170,39 @@ func TestParseInput(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		fail  bool
+	}{
+		{value: "development", want: "development"},
+		{value: "", want: `""`},
+		{value: " padded ", want: `" padded "`},
+		{value: "a #comment", want: `"a #comment"`},
+		{value: "a#b", want: "a#b"},
+		{value: "#a", want: "#a"},
+		{value: "line1\nline2", want: "\"line1\nline2\""},
+		{value: `say "hi"`, want: `say "hi"`},
+		{value: `"quoted"`, fail: true},
+	}
+	for _, tt := range tests {
+		got, err := formatValue(tt.value)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("formatValue(%q) error = nil, want error", tt.value)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("formatValue(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			continue
+		}
+
+		parsed, err := parseInput([]byte("KEY=" + got + "\nNEXT=1"))
+		if err != nil || parsed["KEY"] != tt.value || parsed["NEXT"] != "1" {
+			t.Errorf("parseInput(formatValue(%q)) = %v, %v", tt.value, parsed, err)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package goenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Source provides environment variables from a backend, such as a file, a remote service or the
+// process environment. Implement it to load variables from backends like Vault or Consul.
+//
+// Sources may implement fmt.Stringer to name themselves in errors and provenance.
+type Source interface {
+	Load(ctx context.Context) (map[string]string, error)
+}
+
+// SourceFunc adapts a function to a Source.
+type SourceFunc func(ctx context.Context) (map[string]string, error)
+
+func (f SourceFunc) Load(ctx context.Context) (map[string]string, error) {
+	return f(ctx)
+}
+
+type fileSource struct {
+	filename string
+}
+
+// FileSource returns a Source for a dotenv file, which may be encrypted like the files read by Load.
+func FileSource(filename string) Source {
+	return fileSource{filename: filename}
+}
+
+func (s fileSource) Load(ctx context.Context) (map[string]string, error) {
+	return loadFile(s.filename)
+}
+
+func (s fileSource) String() string {
+	return "file " + s.filename
+}
+
+type fsSource struct {
+	fsys     fs.FS
+	filename string
+}
+
+// FSSource returns a Source for a dotenv file in fsys, such as an embed.FS. Like FileSource, it decrypts encrypted files.
+func FSSource(fsys fs.FS, filename string) Source {
+	return fsSource{fsys: fsys, filename: filename}
+}
+
+func (s fsSource) Load(ctx context.Context) (map[string]string, error) {
+	src, err := fs.ReadFile(s.fsys, s.filename)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFile(src)
+}
+
+func (s fsSource) String() string {
+	return "fs " + s.filename
+}
+
+type mapSource map[string]string
+
+// MapSource returns a Source for the variables in env, for defaults and tests.
+func MapSource(env map[string]string) Source {
+	return mapSource(maps.Clone(env))
+}
+
+func (s mapSource) Load(ctx context.Context) (map[string]string, error) {
+	return maps.Clone(map[string]string(s)), nil
+}
+
+func (s mapSource) String() string {
+	return "map"
+}
+
+type osEnvSource struct{}
+
+// OSEnvSource returns a Source for the environment of the current process.
+func OSEnvSource() Source {
+	return osEnvSource{}
+}
+
+func (osEnvSource) Load(ctx context.Context) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			env[key] = value
+		}
+	}
+	return env, nil
+}
+
+func (osEnvSource) String() string {
+	return "os environment"
+}
+
+// HTTPSource is a Source for an HTTP endpoint that responds to GET requests with a JSON object.
+// Nested objects are flattened like ParseJSON does, joining keys with "_".
+type HTTPSource struct {
+	URL string
+	// Client sends the request. http.DefaultClient is used if it is nil.
+	Client *http.Client
+	// Header is added to the request, e.g. to authenticate.
+	Header http.Header
+	// MaxSize limits the size of the response body. It defaults to 1 MiB.
+	MaxSize int64
+}
+
+func (s *HTTPSource) Load(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range s.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	maxSize := s.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxFileSize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("response exceeds the limit of %d bytes", maxSize)
+	}
+
+	entries, err := parseJSON(s.URL, body, "_")
+	if err != nil {
+		return nil, err
+	}
+	return EntryMap(entries), nil
+}
+
+func (s *HTTPSource) String() string {
+	return "http " + s.URL
+}
+
+type optionalSource struct {
+	Source
+}
+
+// Optional returns a Source that loads no variables instead of failing when the underlying file
+// does not exist, e.g. for a .env.local that only some developers have.
+func Optional(s Source) Source {
+	return optionalSource{Source: s}
+}
+
+func (s optionalSource) Load(ctx context.Context) (map[string]string, error) {
+	env, err := s.Source.Load(ctx)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	return env, err
+}
+
+func (s optionalSource) String() string {
+	return sourceName(s.Source, -1)
+}
+
+// Loader merges the variables of an ordered list of sources. Sources are loaded in the order they
+// are given and a later source overrides the variables of every source before it, so the last
+// source has the highest precedence:
+//
+//	loader := goenv.NewLoader(
+//		goenv.FileSource(".env"),                     // lowest precedence
+//		goenv.Optional(goenv.FileSource(".env.local")),
+//		goenv.OSEnvSource(),                          // highest precedence
+//	)
+//
+// A Loader is itself a Source, so loaders can be nested.
+type Loader struct {
+	sources []Source
+}
+
+// NewLoader returns a Loader for sources, from the lowest precedence to the highest.
+func NewLoader(sources ...Source) *Loader {
+	return &Loader{sources: sources}
+}
+
+// Load returns the merged variables of every source. It fails if any source fails.
+func (l *Loader) Load(ctx context.Context) (map[string]string, error) {
+	env, _, err := l.Resolve(ctx)
+	return env, err
+}
+
+// Resolve returns the merged variables of every source, and the provenance of every variable: the
+// name of the source it was taken from. Sources are named by their String method, if they have one,
+// and by their position otherwise.
+func (l *Loader) Resolve(ctx context.Context) (map[string]string, map[string]string, error) {
+	env := make(map[string]string)
+	provenance := make(map[string]string)
+	for i, s := range l.sources {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		vars, err := s.Load(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("goenv: Failed to load %s: %w", sourceName(s, i), err)
+		}
+		name := sourceName(s, i)
+		for key, value := range vars {
+			env[key] = value
+			provenance[key] = name
+		}
+	}
+	return env, provenance, nil
+}
+
+// Apply loads every source and sets the merged variables in the environment of the current process,
+// overriding variables that are already set. Add OSEnvSource as the last source to keep them instead.
+func (l *Loader) Apply(ctx context.Context) error {
+	env, err := l.Load(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range env {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sourceName names s, the source at index i of a Loader.
+func sourceName(s Source, i int) string {
+	if stringer, ok := s.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	if i < 0 {
+		return "source"
+	}
+	return fmt.Sprintf("source %d", i)
+}
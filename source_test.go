@@ -0,0 +1,175 @@
+package goenv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSources(t *testing.T) {
+	t.Setenv("GOENV_SOURCE_TEST", "os")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"API_KEY": "remote", "DB": {"PORT": 5432}}`))
+	}))
+	defer server.Close()
+
+	fsys := fstest.MapFS{"config/.env": {Data: []byte("A=fs\nB=fs\n")}}
+
+	tests := []struct {
+		name     string
+		source   Source
+		expected map[string]string
+		err      string
+	}{
+		{
+			name:   "File",
+			source: FileSource("testdata/.env.ci"),
+			expected: map[string]string{
+				"APP_ENV": "ci", "DEBUG": "false", "DB_HOST": "localhost", "DB_PORT": "5432", "DB_USER": "ci_runner",
+				"RUN_E2E": "true", "PARALLEL_JOBS": "4", "GIT_COMMIT_SHA": "abcdef123456", "CI_PIPELINE_ID": "78910",
+			},
+		},
+		{
+			name:     "FS",
+			source:   FSSource(fsys, "config/.env"),
+			expected: map[string]string{"A": "fs", "B": "fs"},
+		},
+		{
+			name:     "Map",
+			source:   MapSource(map[string]string{"A": "map"}),
+			expected: map[string]string{"A": "map"},
+		},
+		{
+			name:     "HTTP",
+			source:   &HTTPSource{URL: server.URL, Header: http.Header{"Authorization": {"Bearer token"}}},
+			expected: map[string]string{"API_KEY": "remote", "DB_PORT": "5432"},
+		},
+		{
+			name:   "HTTP status",
+			source: &HTTPSource{URL: server.URL},
+			err:    "unexpected status 403 Forbidden",
+		},
+		{
+			name:   "HTTP size limit",
+			source: &HTTPSource{URL: server.URL, Header: http.Header{"Authorization": {"Bearer token"}}, MaxSize: 10},
+			err:    "response exceeds the limit of 10 bytes",
+		},
+		{
+			name:   "Missing file",
+			source: FileSource("testdata/.env.missing"),
+			err:    "open testdata/.env.missing: no such file or directory",
+		},
+		{
+			name:     "Optional missing file",
+			source:   Optional(FSSource(fsys, ".env.local")),
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.source.Load(context.Background())
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("Load() = got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() = failed with error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Load() = got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	env, err := OSEnvSource().Load(context.Background())
+	if err != nil || env["GOENV_SOURCE_TEST"] != "os" {
+		t.Errorf("OSEnvSource().Load() = got %v, expected GOENV_SOURCE_TEST=os", err)
+	}
+}
+
+func TestLoader(t *testing.T) {
+	t.Setenv("GOENV_LOADER_C", "os")
+
+	custom := SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"GOENV_LOADER_D": "custom"}, nil
+	})
+	loader := NewLoader(
+		MapSource(map[string]string{"GOENV_LOADER_A": "defaults", "GOENV_LOADER_B": "defaults", "GOENV_LOADER_C": "defaults"}),
+		FSSource(fstest.MapFS{".env": {Data: []byte("GOENV_LOADER_B=file\nGOENV_LOADER_C=file\n")}}, ".env"),
+		Optional(FileSource("testdata/.env.local")),
+		OSEnvSource(),
+		custom,
+	)
+
+	env, provenance, err := loader.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() = failed with error: %v", err)
+	}
+
+	expected := map[string][2]string{
+		"GOENV_LOADER_A": {"defaults", "map"},
+		"GOENV_LOADER_B": {"file", "fs .env"},
+		"GOENV_LOADER_C": {"os", "os environment"},
+		"GOENV_LOADER_D": {"custom", "source 4"},
+	}
+	for key, want := range expected {
+		if env[key] != want[0] || provenance[key] != want[1] {
+			t.Errorf("Resolve() = got %s=%q from %q, expected %q from %q", key, env[key], provenance[key], want[0], want[1])
+		}
+	}
+
+	nested := NewLoader(loader, MapSource(map[string]string{"GOENV_LOADER_A": "nested"}))
+	env, err = nested.Load(context.Background())
+	if err != nil || env["GOENV_LOADER_A"] != "nested" || env["GOENV_LOADER_D"] != "custom" {
+		t.Errorf("Load() = got %v, %v, expected nested loaders to merge", env["GOENV_LOADER_A"], err)
+	}
+}
+
+func TestLoader_Errors(t *testing.T) {
+	failing := SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		return nil, errors.New("backend down")
+	})
+
+	_, err := NewLoader(MapSource(nil), failing).Load(context.Background())
+	if expected := "goenv: Failed to load source 1: backend down"; err == nil || err.Error() != expected {
+		t.Errorf("Load() = got error %v, expected %q", err, expected)
+	}
+
+	_, err = NewLoader(FileSource("testdata/.env.missing")).Load(context.Background())
+	if !errors.Is(err, os.ErrNotExist) || !strings.HasPrefix(err.Error(), "goenv: Failed to load file testdata/.env.missing") {
+		t.Errorf("Load() = got error %v, expected a wrapped not exist error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewLoader(MapSource(nil)).Load(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() = got error %v, expected %v", err, context.Canceled)
+	}
+}
+
+func TestLoader_Apply(t *testing.T) {
+	t.Setenv("GOENV_APPLY", "before")
+
+	err := NewLoader(MapSource(map[string]string{"GOENV_APPLY": "after"})).Apply(context.Background())
+	if err != nil {
+		t.Fatalf("Apply() = failed with error: %v", err)
+	}
+	if got := os.Getenv("GOENV_APPLY"); got != "after" {
+		t.Errorf("Apply() = got GOENV_APPLY=%q, expected %q", got, "after")
+	}
+}
@@ -1,19 +1,37 @@
 package goenv
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type tagConfig struct {
 	key          string
+	aliases      []string
 	required     bool
 	defaultValue string
 	hasDefault   bool
+	file         bool
+	sensitive    bool
+	unset        bool
+	layout       string
+	location     *time.Location
+	days         bool
+	unit         string
+	rules        []rule
 }
 
 // Struct populates a struct with values from environment variables.
@@ -23,20 +41,81 @@ type tagConfig struct {
 // The function requires a pointer to a struct as input. It will recursively
 // process nested structs if they are encountered.
 //
+// Struct does not stop at the first failing field. Every missing required
+// variable and every value that cannot be parsed is collected, and returned
+// together as a ValidationErrors holding one *FieldError per field.
+//
 // Supported field types:
 //   - string
 //   - int, int8, int16, int32, int64
 //   - uint, uint8, uint16, uint32, uint64
 //   - float32, float64
-//   - bool
-//   - time.Duration
-//   - time.Time (uses Golang's time formats)
+//   - bool (also accepts words such as yes, no, on and off, see DefaultBoolWords)
+//   - time.Duration (see the days and unit options)
+//   - ByteSize (e.g. 512MiB or 2GB)
+//   - time.Time (uses Golang's time formats, see the layout and tz options)
+//   - url.URL, *url.URL
+//   - net.IP, netip.Addr, netip.Prefix
+//   - *regexp.Regexp
+//   - []byte (values prefixed with "base64:" or "hex:" are decoded)
+//   - time.Location, *time.Location (IANA time zone names, e.g. Europe/Copenhagen)
+//   - os.FileMode (octal, e.g. 0640)
 //   - nested structs (processed recursively)
 //
+// After a struct has been populated, including its nested structs, Struct calls
+// its Validate method if it implements Validator. Nested structs are validated
+// before the structs containing them, and the errors are merged with the field
+// errors, attributed to the path of the struct.
+//
 // Struct tag format:
 //   - Use `goenv:"ENV_VAR_NAME"` to specify the environment variable name
-//   - Fields without the goenv tag are ignored
+//   - Fields without the goenv tag are ignored, unless WithAutoKeys is used
+//   - Use `goenv:"-"` to always ignore a field, including nested structs
 //   - Unexported fields are skipped automatically
+//   - Options follow the key, separated by commas. Option values cannot contain commas
+//   - Use `envPrefix:"PREFIX_"` on a nested struct field to prepend PREFIX_ to the keys of its fields.
+//     Prefixes compose across nesting levels and with WithPrefix
+//
+// If a variable is unset or empty, but the variable of the same name with a
+// "_FILE" suffix holds a path, the value is read from that file instead. With
+// the file option, as in `goenv:"TLS_CERT,file"`, the variable itself holds
+// the path of the file to read. Trailing line breaks are trimmed from file
+// contents. See WithMaxFileSize and WithFilePermissions.
+//
+// Time options:
+//   - layout=LAYOUT: parse time.Time fields with a Go layout such as 02/01/2006, or the name of
+//     a layout constant of the time package such as RFC1123. Use unix, unixmilli, unixmicro or
+//     unixnano for Unix timestamps. Without a layout a list of common layouts is tried
+//   - tz=NAME: the IANA time zone, e.g. Europe/Copenhagen, of values without a zone offset.
+//     Defaults to UTC
+//
+// Duration options:
+//   - days: accept the units "d" for days and "w" for weeks in time.Duration fields, see ParseDuration
+//   - unit=UNIT: interpret numbers without a unit in time.Duration fields in UNIT, e.g. unit=s
+//     reads "30" as 30 seconds. Days and weeks are accepted as with days
+//
+// Keys and aliases:
+//   - Use `goenv:"DB_URL|DATABASE_URL"` or `goenv:"DB_URL,alias=DATABASE_URL"` to fall back to
+//     other keys. The first key that is set wins. Reading a field from any key but the first is
+//     logged as a deprecation warning, see WithLogger
+//
+// Secrets:
+//   - sensitive: the value is masked in errors, and by Dump and Redacted
+//   - unset: the variable, and its _FILE variant, is removed from the process
+//     environment after it has been read, so child processes do not inherit it
+//
+// Validation options:
+//   - min=N, max=N: bounds for numbers and durations, or the length of strings
+//   - oneof=a|b|c: the value must be one of the listed values
+//   - match=REGEX: the value must match the regular expression
+//   - notempty: the value must not be empty or only whitespace, even if the variable is optional
+//   - file_exists: the value must be the path of an existing file or directory
+//   - url: the value must be an absolute URL with a scheme and host
+//   - hostport: the value must be a host:port pair with a valid port
+//
+// Validation failures are reported as FieldErrors like any other error.
+// Optional variables that are unset and have no default are not validated,
+// except for notempty.
 //
 // Example:
 //
@@ -55,7 +134,7 @@ type tagConfig struct {
 //	if err != nil {
 //		return fmt.Errorf("failed to load database config: %w", err)
 //	}
-func Struct(v any) error {
+func Struct(v any, opts ...Option) error {
 	val := reflect.ValueOf(v)
 	if val.Kind() != reflect.Pointer {
 		return fmt.Errorf("goenv - expected pointer to struct")
@@ -66,57 +145,109 @@ func Struct(v any) error {
 		return fmt.Errorf("goenv - expected pointer to struct")
 	}
 
-	for i := range val.NumField() {
-		field := val.Field(i)
-		fieldName := val.Type().Field(i).Name
-		if !field.CanSet() {
-			continue
-		}
+	d := decoder{opts: newOptions(opts)}
+	w := structWalker{opts: d.opts, field: d.populateField, leave: d.validateStruct}
+	w.walk(val, "", d.opts.prefix)
+	if len(d.errs) > 0 {
+		return d.errs
+	}
 
-		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Time{}) {
-			if err := Struct(field.Addr().Interface()); err != nil {
-				return err
-			}
-			continue
-		}
+	return nil
+}
 
-		tag := val.Type().Field(i).Tag.Get("goenv")
-		if tag == "" {
-			continue
-		}
+// StructWithPrefix populates a struct like Struct, prepending prefix to every environment variable key.
+//
+// It is shorthand for Struct(v, WithPrefix(prefix)).
+func StructWithPrefix(v any, prefix string) error {
+	return Struct(v, WithPrefix(prefix))
+}
 
-		tagConfig, err := parseTag(tag)
-		if err != nil {
-			return fmt.Errorf("goenv - error on field %s: %s", fieldName, err.Error())
-		}
+// decoder holds the state of a single call to Struct.
+type decoder struct {
+	opts *options
+	errs ValidationErrors
+}
 
-		value, found := os.LookupEnv(tagConfig.key)
-		if !found || value == "" {
-			if tagConfig.required {
-				return fmt.Errorf("goenv - error on field %s: missing required env var", fieldName)
-			} else if tagConfig.hasDefault {
-				value = tagConfig.defaultValue
+// populateField sets f from its environment variable, recording a FieldError if it fails.
+func (d *decoder) populateField(f envField, err error) {
+	if err != nil {
+		d.fieldError(f, "", err)
+		return
+	}
+
+	key, value, found, err := d.opts.lookupField(f)
+	if f.tag.unset && d.opts.env == nil {
+		for _, k := range append([]string{f.key}, f.aliases...) {
+			os.Unsetenv(k)
+			os.Unsetenv(k + "_FILE")
+		}
+	}
+	if err != nil {
+		d.fieldError(f, "", err)
+		return
+	}
+	if key != f.key {
+		d.opts.warnDeprecated(f, key)
+		f.key = key
+	}
+	if !found || value == "" {
+		if f.tag.required {
+			d.fieldError(f, "", ErrMissingRequired)
+			return
+		} else if f.tag.hasDefault {
+			value = f.tag.defaultValue
+		} else {
+			// env is optional with no default, leave the field as is
+			// unless a rule demands a value
+			if err := validateEmpty(f.tag.rules); err != nil {
+				d.fieldError(f, "", err)
 			}
-			// env is optional with no default
+			return
 		}
+	}
 
-		if err := setFieldValue(field, value); err != nil {
-			return fmt.Errorf("goenv - error on field %s: %s", fieldName, err.Error())
+	if f.tag.file {
+		contents, err := d.opts.readSecretFile(value)
+		if err != nil {
+			d.fieldError(f, value, err)
+			return
 		}
+		value = contents
 	}
 
-	return nil
+	// errors of sensitive fields are built from the masked value, so they never hold the secret
+	shown := value
+	if f.tag.sensitive {
+		shown = redactedValue
+	}
+
+	if err := setFieldValue(f.value, value, shown, f.tag, d.opts); err != nil {
+		d.fieldError(f, value, err)
+		return
+	}
+
+	if err := validateField(f.value, value, shown, f.tag.rules); err != nil {
+		d.fieldError(f, value, err)
+	}
+}
+
+// fieldError records err for f, masking value if f is sensitive.
+func (d *decoder) fieldError(f envField, value string, err error) {
+	if f.tag.sensitive && value != "" {
+		value = redactedValue
+	}
+	d.errs = append(d.errs, &FieldError{Field: f.path, Key: f.key, Value: value, Err: err})
 }
 
 func parseTag(tag string) (tagConfig, error) {
 	parts := strings.Split(tag, ",")
 
+	keys := strings.Split(parts[0], "|")
 	config := tagConfig{
-		key: strings.TrimSpace(parts[0]),
+		key: strings.TrimSpace(keys[0]),
 	}
-
-	if config.key == "" {
-		return tagConfig{}, fmt.Errorf("empty env var key")
+	for _, alias := range keys[1:] {
+		config.aliases = append(config.aliases, strings.TrimSpace(alias))
 	}
 
 	for _, part := range parts[1:] {
@@ -124,12 +255,47 @@ func parseTag(tag string) (tagConfig, error) {
 
 		if part == "required" {
 			config.required = true
+		} else if part == "file" {
+			config.file = true
+		} else if part == "sensitive" {
+			config.sensitive = true
+		} else if part == "unset" {
+			config.unset = true
+		} else if strings.HasPrefix(part, "alias=") {
+			for _, alias := range strings.Split(strings.TrimPrefix(part, "alias="), "|") {
+				config.aliases = append(config.aliases, strings.TrimSpace(alias))
+			}
+		} else if strings.HasPrefix(part, "layout=") {
+			config.layout = timeLayout(strings.TrimPrefix(part, "layout="))
+		} else if part == "days" {
+			config.days = true
+		} else if strings.HasPrefix(part, "unit=") {
+			config.unit = strings.TrimPrefix(part, "unit=")
+			if _, err := ParseDuration("1" + config.unit); err != nil {
+				return tagConfig{}, fmt.Errorf("invalid duration unit %q", config.unit)
+			}
+		} else if strings.HasPrefix(part, "tz=") {
+			loc, err := time.LoadLocation(strings.TrimPrefix(part, "tz="))
+			if err != nil {
+				return tagConfig{}, fmt.Errorf("invalid tz: %s", err.Error())
+			}
+			config.location = loc
 		} else if strings.HasPrefix(part, "default=") {
 			config.defaultValue = strings.TrimPrefix(part, "default=")
 			config.hasDefault = true
+		} else if name, arg, _ := strings.Cut(part, "="); isRuleName(name) {
+			r, err := newRule(name, arg)
+			if err != nil {
+				return tagConfig{}, err
+			}
+			config.rules = append(config.rules, r)
 		}
 	}
 
+	if slices.Contains(config.aliases, "") {
+		return tagConfig{}, fmt.Errorf("empty env var alias")
+	}
+
 	if config.hasDefault && config.required {
 		return tagConfig{}, fmt.Errorf("cannot be both required and have default: %s", config.key)
 	}
@@ -137,47 +303,111 @@ func parseTag(tag string) (tagConfig, error) {
 	return config, nil
 }
 
-func setFieldValue(field reflect.Value, value string) error {
+// setFieldValue parses value in to field. Errors show shown instead of value, which is masked for
+// sensitive fields.
+func setFieldValue(field reflect.Value, value, shown string, tag tagConfig, o *options) error {
 	switch field.Interface().(type) {
 	case string:
 		field.SetString(value)
 	case int, int8, int16, int32, int64:
 		intVal, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid int value %q", value)
+			return fmt.Errorf("invalid int value %q", shown)
 		}
 		field.SetInt(intVal)
 	case uint, uint8, uint16, uint32, uint64:
 		uintVal, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid uint value %q", value)
+			return fmt.Errorf("invalid uint value %q", shown)
 		}
 		field.SetUint(uintVal)
 	case float32, float64:
 		floatVal, err := strconv.ParseFloat(value, 64)
 		if err != nil {
-			return fmt.Errorf("invalid float value %q", err)
+			return fmt.Errorf("invalid float value %q", shown)
 		}
 		field.SetFloat(floatVal)
 	case bool:
-		boolVal, err := strconv.ParseBool(value)
+		boolVal, err := o.parseBool(value)
 		if err != nil {
-			return fmt.Errorf("invalid bool value %q", err)
+			return fmt.Errorf("invalid bool value %q", shown)
 		}
 		field.SetBool(boolVal)
 	case time.Duration:
-		durVal, err := time.ParseDuration(value)
+		durVal, err := parseDurationValue(value, tag.unit, tag.days)
 		if err != nil {
-			return fmt.Errorf("invalid duration value %q", value)
+			return fmt.Errorf("invalid duration value %q", shown)
 		}
 		field.Set(reflect.ValueOf(durVal))
 	case time.Time:
-		timeVal, err := parseTimeValue(value)
+		timeVal, err := parseTimeValue(value, tag.layout, tag.location)
 		if err != nil {
-			return fmt.Errorf("invalid time value %q", value)
+			return fmt.Errorf("invalid time value %q: %s", shown, err.Error())
 		}
 		field.Set(reflect.ValueOf(timeVal))
 		return nil
+	case ByteSize:
+		sizeVal, err := ParseByteSize(value)
+		if err != nil {
+			return fmt.Errorf("invalid byte size value %q", shown)
+		}
+		field.SetUint(uint64(sizeVal))
+	case url.URL, *url.URL:
+		urlVal, err := url.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid url value %q", shown)
+		}
+		if field.Kind() == reflect.Pointer {
+			field.Set(reflect.ValueOf(urlVal))
+		} else {
+			field.Set(reflect.ValueOf(*urlVal))
+		}
+	case net.IP:
+		ipVal := net.ParseIP(value)
+		if ipVal == nil {
+			return fmt.Errorf("invalid ip value %q", shown)
+		}
+		field.Set(reflect.ValueOf(ipVal))
+	case netip.Addr:
+		addrVal, err := netip.ParseAddr(value)
+		if err != nil {
+			return fmt.Errorf("invalid ip value %q", shown)
+		}
+		field.Set(reflect.ValueOf(addrVal))
+	case netip.Prefix:
+		prefixVal, err := netip.ParsePrefix(value)
+		if err != nil {
+			return fmt.Errorf("invalid prefix value %q", shown)
+		}
+		field.Set(reflect.ValueOf(prefixVal))
+	case *regexp.Regexp:
+		reVal, err := regexp.Compile(value)
+		if err != nil {
+			return fmt.Errorf("invalid regexp value %q", shown)
+		}
+		field.Set(reflect.ValueOf(reVal))
+	case []byte:
+		bytesVal, err := parseBytesValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid bytes value %q", shown)
+		}
+		field.SetBytes(bytesVal)
+	case time.Location, *time.Location:
+		locVal, err := time.LoadLocation(value)
+		if err != nil {
+			return fmt.Errorf("invalid location value %q", shown)
+		}
+		if field.Kind() == reflect.Pointer {
+			field.Set(reflect.ValueOf(locVal))
+		} else {
+			field.Set(reflect.ValueOf(locVal).Elem())
+		}
+	case os.FileMode:
+		modeVal, err := strconv.ParseUint(value, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid file mode value %q", shown)
+		}
+		field.SetUint(modeVal)
 	default:
 		return fmt.Errorf("unsupported field type %s", field.Kind())
 	}
@@ -185,25 +415,70 @@ func setFieldValue(field reflect.Value, value string) error {
 	return nil
 }
 
-func parseTimeValue(value string) (time.Time, error) {
-	formats := []string{
-		time.RFC3339,
-		time.RFC3339Nano,
-		time.DateTime,
-		time.DateOnly,
-		time.TimeOnly,
-		time.Kitchen,
-		time.Stamp,
-		time.StampMilli,
-		time.StampMicro,
-		time.StampNano,
+// parseBytesValue decodes value prefixed with "base64:" or "hex:".
+// Values without a prefix are used as is.
+func parseBytesValue(value string) ([]byte, error) {
+	if encoded, ok := strings.CutPrefix(value, "base64:"); ok {
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+	if encoded, ok := strings.CutPrefix(value, "hex:"); ok {
+		return hex.DecodeString(encoded)
 	}
+	return []byte(value), nil
+}
+
+// mixedCaseWords are initialisms that contain lower case letters, which would otherwise be split in two.
+var mixedCaseWords = []string{"OAuth"}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, value); err == nil {
-			return t, nil
+// fieldNameToKey converts a Go field name to an upper snake case key,
+// e.g. "ReadTimeout" becomes "READ_TIMEOUT" and "APIKey" becomes "API_KEY".
+//
+// A single lower case letter followed by a digit belongs to the initialism before it, so "IPv6Addr"
+// becomes "IPV6_ADDR" and "APIv2URL" becomes "APIV2_URL", and so does a plural "s" at the end of a
+// word, so "AllowedIPs" becomes "ALLOWED_IPS" and "URLs" becomes "URLS". Of the other initialisms with lower case
+// letters, only those in mixedCaseWords are recognized, e.g. "OAuth2Token" becomes "OAUTH2_TOKEN".
+func fieldNameToKey(name string) string {
+	for _, word := range mixedCaseWords {
+		name = replaceWord(name, word, strings.ToUpper(word))
+	}
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// e.g. the "v" of "IPv6" and the "s" of "IPs" are not the start of a word
+			versionLetter := nextIsLower && i+2 < len(runes) && unicode.IsDigit(runes[i+2])
+			plural := nextIsLower && runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2]))
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower && !versionLetter && !plural) {
+				b.WriteByte('_')
+			}
 		}
+		b.WriteRune(unicode.ToUpper(r))
 	}
 
-	return time.Time{}, fmt.Errorf("unknown time format: %s", value)
+	return b.String()
+}
+
+// replaceWord replaces every occurrence of word in name that is not followed by a lower case letter,
+// as it would then be part of a longer word.
+func replaceWord(name, word, replacement string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(name, word)
+		if i == -1 {
+			b.WriteString(name)
+			return b.String()
+		}
+		end := i + len(word)
+		r, _ := utf8.DecodeRuneInString(name[end:])
+		b.WriteString(name[:i])
+		if end < len(name) && unicode.IsLower(r) {
+			b.WriteString(word)
+		} else {
+			b.WriteString(replacement)
+		}
+		name = name[end:]
+	}
 }
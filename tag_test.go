@@ -1,8 +1,15 @@
 package goenv
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 )
@@ -467,3 +474,422 @@ func TestStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestStructErrors(t *testing.T) {
+	os.Setenv("TEST_INVALID_INT", "not_a_number")
+	os.Setenv("DB_PORT", "port")
+	t.Cleanup(func() {
+		os.Unsetenv("TEST_INVALID_INT")
+		os.Unsetenv("DB_PORT")
+	})
+
+	input := &struct {
+		IntField int    `goenv:"TEST_INVALID_INT"`
+		Missing  string `goenv:"TEST_MISSING,required"`
+		Database struct {
+			Port int `goenv:"DB_PORT"`
+		}
+	}{}
+
+	err := Struct(input)
+	if err == nil {
+		t.Fatalf("Struct() error = nil, want errors")
+	}
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct() error = %T, want ValidationErrors", err)
+	}
+
+	want := []FieldError{
+		{Field: "IntField", Key: "TEST_INVALID_INT", Value: "not_a_number"},
+		{Field: "Missing", Key: "TEST_MISSING", Err: ErrMissingRequired},
+		{Field: "Database.Port", Key: "DB_PORT", Value: "port"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("len(ValidationErrors) = %d, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, w := range want {
+		got := errs[i]
+		if got.Field != w.Field || got.Key != w.Key || got.Value != w.Value {
+			t.Errorf("errs[%d] = {%s %s %q}, want {%s %s %q}", i, got.Field, got.Key, got.Value, w.Field, w.Key, w.Value)
+		}
+		if w.Err != nil && !errors.Is(got, w.Err) {
+			t.Errorf("errs[%d] = %v, want wrapping %v", i, got, w.Err)
+		}
+	}
+
+	if !errors.Is(err, ErrMissingRequired) {
+		t.Errorf("errors.Is(err, ErrMissingRequired) = false, want true")
+	}
+}
+
+func TestStructPrefix(t *testing.T) {
+	type databaseConfig struct {
+		Host string `goenv:"HOST"`
+		Port int    `goenv:"PORT,default=5432"`
+	}
+	type config struct {
+		Name    string         `goenv:"NAME"`
+		Primary databaseConfig `envPrefix:"PRIMARY_"`
+		Replica databaseConfig `envPrefix:"REPLICA_"`
+		Nested  struct {
+			Cache struct {
+				Host string `goenv:"HOST"`
+			} `envPrefix:"CACHE_"`
+		} `envPrefix:"NESTED_"`
+	}
+
+	env := map[string]string{
+		"MYAPP_NAME":              "myapp",
+		"MYAPP_PRIMARY_HOST":      "primary.internal",
+		"MYAPP_PRIMARY_PORT":      "6543",
+		"MYAPP_REPLICA_HOST":      "replica.internal",
+		"MYAPP_NESTED_CACHE_HOST": "cache.internal",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	t.Cleanup(func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	})
+
+	var cfg config
+	if err := StructWithPrefix(&cfg, "MYAPP_"); err != nil {
+		t.Fatalf("StructWithPrefix() error = %v", err)
+	}
+
+	if cfg.Name != "myapp" {
+		t.Errorf("Name = %v, want %v", cfg.Name, "myapp")
+	}
+	if cfg.Primary.Host != "primary.internal" || cfg.Primary.Port != 6543 {
+		t.Errorf("Primary = %+v, want {primary.internal 6543}", cfg.Primary)
+	}
+	if cfg.Replica.Host != "replica.internal" || cfg.Replica.Port != 5432 {
+		t.Errorf("Replica = %+v, want {replica.internal 5432}", cfg.Replica)
+	}
+	if cfg.Nested.Cache.Host != "cache.internal" {
+		t.Errorf("Nested.Cache.Host = %v, want %v", cfg.Nested.Cache.Host, "cache.internal")
+	}
+
+	var unprefixed config
+	err := Struct(&unprefixed, WithPrefix("OTHER_"))
+	if err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+	if unprefixed.Primary.Host != "" {
+		t.Errorf("Primary.Host = %v, want empty", unprefixed.Primary.Host)
+	}
+}
+
+func TestStructAutoKeys(t *testing.T) {
+	type Embedded struct {
+		LogLevel string `goenv:",default=info"`
+	}
+	type config struct {
+		Embedded
+		ReadTimeout time.Duration
+		APIKey      string `goenv:",required"`
+		Explicit    string `goenv:"EXPLICIT_NAME"`
+		Ignored     string `goenv:"-"`
+		Database    struct {
+			Port int
+		}
+		Cache struct {
+			Host string
+		} `envPrefix:"REDIS_"`
+		Skipped struct {
+			Host string
+		} `goenv:"-"`
+	}
+
+	env := map[string]string{
+		"APP_READ_TIMEOUT":  "5s",
+		"APP_API_KEY":       "secret",
+		"APP_EXPLICIT_NAME": "explicit",
+		"APP_IGNORED":       "ignored",
+		"APP_DATABASE_PORT": "5432",
+		"APP_REDIS_HOST":    "redis.internal",
+		"APP_SKIPPED_HOST":  "skipped",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	t.Cleanup(func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	})
+
+	var cfg config
+	if err := Struct(&cfg, WithPrefix("APP_"), WithAutoKeys()); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+
+	if cfg.LogLevel != "info" {
+		t.Errorf("LogLevel = %v, want %v", cfg.LogLevel, "info")
+	}
+	if cfg.ReadTimeout != 5*time.Second {
+		t.Errorf("ReadTimeout = %v, want %v", cfg.ReadTimeout, 5*time.Second)
+	}
+	if cfg.APIKey != "secret" {
+		t.Errorf("APIKey = %v, want %v", cfg.APIKey, "secret")
+	}
+	if cfg.Explicit != "explicit" {
+		t.Errorf("Explicit = %v, want %v", cfg.Explicit, "explicit")
+	}
+	if cfg.Ignored != "" {
+		t.Errorf("Ignored = %v, want empty", cfg.Ignored)
+	}
+	if cfg.Database.Port != 5432 {
+		t.Errorf("Database.Port = %v, want %v", cfg.Database.Port, 5432)
+	}
+	if cfg.Cache.Host != "redis.internal" {
+		t.Errorf("Cache.Host = %v, want %v", cfg.Cache.Host, "redis.internal")
+	}
+	if cfg.Skipped.Host != "" {
+		t.Errorf("Skipped.Host = %v, want empty", cfg.Skipped.Host)
+	}
+
+	var withoutAuto config
+	err := Struct(&withoutAuto, WithPrefix("APP_"))
+	if err == nil {
+		t.Fatalf("Struct() error = nil, want error for tags without key")
+	}
+	if withoutAuto.ReadTimeout != 0 {
+		t.Errorf("ReadTimeout = %v, want 0 without WithAutoKeys", withoutAuto.ReadTimeout)
+	}
+}
+
+func TestFieldNameToKey(t *testing.T) {
+	tests := map[string]string{
+		"ReadTimeout": "READ_TIMEOUT",
+		"APIKey":      "API_KEY",
+		"Port":        "PORT",
+		"ID":          "ID",
+		"UserID":      "USER_ID",
+		"HTTPServer":  "HTTP_SERVER",
+		"V2Endpoint":  "V2_ENDPOINT",
+		"maxConns":    "MAX_CONNS",
+		"IPv6Addr":    "IPV6_ADDR",
+		"ListenIPv4":  "LISTEN_IPV4",
+		"APIv2URL":    "APIV2_URL",
+		"OAuth2Token": "OAUTH2_TOKEN",
+		"GoogleOAuth": "GOOGLE_OAUTH",
+		"AllowedIPs":  "ALLOWED_IPS",
+		"URLs":        "URLS",
+		"IDs":         "IDS",
+		"UserIDsFile": "USER_IDS_FILE",
+		"HTTPServers": "HTTP_SERVERS",
+	}
+	for name, want := range tests {
+		if got := fieldNameToKey(name); got != want {
+			t.Errorf("fieldNameToKey(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestStructAliases(t *testing.T) {
+	type config struct {
+		URL   string `goenv:"DB_URL|DATABASE_URL|DB_CONN,required"`
+		Token string `goenv:"AUTH_TOKEN,alias=API_TOKEN"`
+		Port  int    `goenv:"PORT|LISTEN_PORT,default=8080"`
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		want     config
+		warnings []string
+		fail     bool
+	}{
+		{
+			name: "Primary keys",
+			env:  map[string]string{"APP_DB_URL": "postgres://new", "APP_DATABASE_URL": "postgres://old", "APP_AUTH_TOKEN": "new"},
+			want: config{URL: "postgres://new", Token: "new", Port: 8080},
+		},
+		{
+			name:     "Deprecated aliases",
+			env:      map[string]string{"APP_DB_CONN": "postgres://oldest", "APP_API_TOKEN": "old", "APP_LISTEN_PORT": "9090"},
+			want:     config{URL: "postgres://oldest", Token: "old", Port: 9090},
+			warnings: []string{"APP_DB_CONN", "APP_API_TOKEN", "APP_LISTEN_PORT"},
+		},
+		{
+			name:     "First alias that is set wins",
+			env:      map[string]string{"APP_DATABASE_URL": "postgres://old", "APP_DB_CONN": "postgres://oldest"},
+			want:     config{URL: "postgres://old", Port: 8080},
+			warnings: []string{"APP_DATABASE_URL"},
+		},
+		{
+			name: "Missing required with aliases",
+			env:  map[string]string{},
+			fail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs strings.Builder
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+			var cfg config
+			err := Struct(&cfg, WithPrefix("APP_"), WithEnvironment(tt.env), WithLogger(logger))
+			if tt.fail {
+				if err == nil {
+					t.Errorf("Struct() error = nil, fail %v", tt.fail)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Struct() error = %v", err)
+			}
+			if cfg != tt.want {
+				t.Errorf("Struct() = %+v, want %+v", cfg, tt.want)
+			}
+
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			if len(tt.warnings) == 0 {
+				if logs.Len() != 0 {
+					t.Errorf("unexpected warnings: %s", logs.String())
+				}
+				return
+			}
+			if len(lines) != len(tt.warnings) {
+				t.Fatalf("warnings = %v, want %v", lines, tt.warnings)
+			}
+			for i, key := range tt.warnings {
+				if !strings.Contains(lines[i], "key="+key) || !strings.Contains(lines[i], "level=WARN") {
+					t.Errorf("warning %d = %q, want warning for %s", i, lines[i], key)
+				}
+			}
+		})
+	}
+}
+
+func TestStructAliasParseError(t *testing.T) {
+	env := map[string]string{"OLD_PORT": "not_a_number"}
+
+	var cfg struct {
+		Port int `goenv:"PORT|OLD_PORT"`
+	}
+	err := Struct(&cfg, WithEnvironment(env), WithLogger(nil))
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("Struct() error = %v, want one field error", err)
+	}
+	if errs[0].Key != "OLD_PORT" {
+		t.Errorf("FieldError.Key = %v, want %v", errs[0].Key, "OLD_PORT")
+	}
+}
+
+type testTypesConfig struct {
+	URL      url.URL        `goenv:"TEST_URL"`
+	URLPtr   *url.URL       `goenv:"TEST_URL"`
+	IP       net.IP         `goenv:"TEST_IP"`
+	Addr     netip.Addr     `goenv:"TEST_ADDR"`
+	Prefix   netip.Prefix   `goenv:"TEST_PREFIX"`
+	Pattern  *regexp.Regexp `goenv:"TEST_PATTERN"`
+	Raw      []byte         `goenv:"TEST_RAW"`
+	Base64   []byte         `goenv:"TEST_BASE64"`
+	Hex      []byte         `goenv:"TEST_HEX"`
+	Location time.Location  `goenv:"TEST_LOCATION"`
+	LocPtr   *time.Location `goenv:"TEST_LOCATION"`
+	Mode     os.FileMode    `goenv:"TEST_MODE"`
+}
+
+func TestStructTypes(t *testing.T) {
+	env := map[string]string{
+		"TEST_URL":      "https://user@api.example.com:8443/v1?q=1",
+		"TEST_IP":       "10.0.0.1",
+		"TEST_ADDR":     "::1",
+		"TEST_PREFIX":   "10.0.0.0/8",
+		"TEST_PATTERN":  "^[a-z]+$",
+		"TEST_RAW":      "raw bytes",
+		"TEST_BASE64":   "base64:aGVsbG8=",
+		"TEST_HEX":      "hex:68656c6c6f",
+		"TEST_LOCATION": "UTC",
+		"TEST_MODE":     "0640",
+	}
+
+	var cfg testTypesConfig
+	if err := Struct(&cfg, WithEnvironment(env)); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+
+	if cfg.URL.Host != "api.example.com:8443" || cfg.URL.Path != "/v1" || cfg.URL.User.Username() != "user" {
+		t.Errorf("URL = %v", cfg.URL.String())
+	}
+	if cfg.URLPtr == nil || cfg.URLPtr.String() != env["TEST_URL"] {
+		t.Errorf("URLPtr = %v, want %v", cfg.URLPtr, env["TEST_URL"])
+	}
+	if !cfg.IP.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("IP = %v, want %v", cfg.IP, "10.0.0.1")
+	}
+	if cfg.Addr != netip.IPv6Loopback() {
+		t.Errorf("Addr = %v, want %v", cfg.Addr, "::1")
+	}
+	if cfg.Prefix != netip.MustParsePrefix("10.0.0.0/8") {
+		t.Errorf("Prefix = %v, want %v", cfg.Prefix, "10.0.0.0/8")
+	}
+	if cfg.Pattern == nil || !cfg.Pattern.MatchString("abc") || cfg.Pattern.MatchString("ABC") {
+		t.Errorf("Pattern = %v, want %v", cfg.Pattern, env["TEST_PATTERN"])
+	}
+	if string(cfg.Raw) != "raw bytes" || string(cfg.Base64) != "hello" || string(cfg.Hex) != "hello" {
+		t.Errorf("bytes = %q %q %q, want %q %q %q", cfg.Raw, cfg.Base64, cfg.Hex, "raw bytes", "hello", "hello")
+	}
+	if cfg.Location.String() != "UTC" || cfg.LocPtr == nil || cfg.LocPtr.String() != "UTC" {
+		t.Errorf("Location = %v %v, want UTC", cfg.Location.String(), cfg.LocPtr)
+	}
+	if cfg.Mode != 0o640 {
+		t.Errorf("Mode = %v, want %v", cfg.Mode, os.FileMode(0o640))
+	}
+
+	marshalled, err := MarshalStruct(cfg)
+	if err != nil {
+		t.Fatalf("MarshalStruct() error = %v", err)
+	}
+	var roundTrip testTypesConfig
+	if err := Struct(&roundTrip, WithEnvironment(marshalled)); err != nil {
+		t.Fatalf("Struct(MarshalStruct()) error = %v", err)
+	}
+	if roundTrip.URL.String() != cfg.URL.String() || roundTrip.Prefix != cfg.Prefix ||
+		string(roundTrip.Raw) != string(cfg.Raw) || roundTrip.Mode != cfg.Mode ||
+		roundTrip.Pattern.String() != cfg.Pattern.String() || !roundTrip.IP.Equal(cfg.IP) {
+		t.Errorf("Struct(MarshalStruct()) = %+v, want %+v", roundTrip, cfg)
+	}
+}
+
+func TestStructTypesErrors(t *testing.T) {
+	env := map[string]string{
+		"TEST_URL":      "http://[::1",
+		"TEST_IP":       "10.0.0.256",
+		"TEST_ADDR":     "localhost",
+		"TEST_PREFIX":   "10.0.0.0/33",
+		"TEST_PATTERN":  "[a-z",
+		"TEST_BASE64":   "base64:not base64",
+		"TEST_HEX":      "hex:xyz",
+		"TEST_LOCATION": "Nowhere/Special",
+		"TEST_MODE":     "0999",
+	}
+
+	var cfg testTypesConfig
+	var errs ValidationErrors
+	if err := Struct(&cfg, WithEnvironment(env)); !errors.As(err, &errs) {
+		t.Fatalf("Struct() error = %v, want ValidationErrors", err)
+	}
+
+	var fields []string
+	for _, fe := range errs {
+		if fe.Key == "" || fe.Value != env[fe.Key] {
+			t.Errorf("FieldError = %+v, want key and raw value", fe)
+		}
+		fields = append(fields, fe.Field)
+	}
+	want := "URL,URLPtr,IP,Addr,Prefix,Pattern,Base64,Hex,Location,LocPtr,Mode"
+	if strings.Join(fields, ",") != want {
+		t.Errorf("error fields = %v, want %v", strings.Join(fields, ","), want)
+	}
+}
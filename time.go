@@ -0,0 +1,211 @@
+package goenv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTimeLayouts are tried in order when a time.Time field has no layout option.
+var defaultTimeLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	time.DateTime,
+	time.DateOnly,
+	time.TimeOnly,
+	time.Kitchen,
+	time.Stamp,
+	time.StampMilli,
+	time.StampMicro,
+	time.StampNano,
+}
+
+// namedTimeLayouts maps the names of the layout constants of the time package to their layouts,
+// so layouts containing commas can be used in struct tags.
+var namedTimeLayouts = map[string]string{
+	"Layout":      time.Layout,
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// Layouts for Unix timestamps in the layout tag option.
+const (
+	layoutUnix      = "unix"
+	layoutUnixMilli = "unixmilli"
+	layoutUnixMicro = "unixmicro"
+	layoutUnixNano  = "unixnano"
+)
+
+// timeLayout resolves the value of a layout tag option to a Go layout.
+func timeLayout(layout string) string {
+	if named, ok := namedTimeLayouts[layout]; ok {
+		return named
+	}
+	return layout
+}
+
+// parseTimeValue parses value with layout, or with each of the default layouts if layout is empty.
+// Values without a zone offset are interpreted in loc, or UTC if loc is nil.
+func parseTimeValue(value, layout string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch layout {
+	case layoutUnix, layoutUnixMilli, layoutUnixMicro, layoutUnixNano:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("expected a %s timestamp", layout)
+		}
+		return unixTime(n, layout).In(loc), nil
+	}
+
+	layouts := defaultTimeLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+
+	for _, format := range layouts {
+		if t, err := time.ParseInLocation(format, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("tried layouts %s", strings.Join(layouts, ", "))
+}
+
+// formatTimeValue formats t so that parseTimeValue with the same layout and loc parses it back.
+func formatTimeValue(t time.Time, layout string, loc *time.Location) string {
+	switch layout {
+	case layoutUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case layoutUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case layoutUnixMicro:
+		return strconv.FormatInt(t.UnixMicro(), 10)
+	case layoutUnixNano:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	case "":
+		layout = time.RFC3339Nano
+	}
+
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return t.Format(layout)
+}
+
+func unixTime(n int64, layout string) time.Time {
+	switch layout {
+	case layoutUnixMilli:
+		return time.UnixMilli(n)
+	case layoutUnixMicro:
+		return time.UnixMicro(n)
+	case layoutUnixNano:
+		return time.Unix(0, n)
+	}
+	return time.Unix(n, 0)
+}
+
+// ParseDuration parses a duration like time.ParseDuration, but also accepts the units
+// "d" for days of 24 hours and "w" for weeks of 7 days, e.g. "7d" or "1w2d12h".
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	var d time.Duration
+	for s != "" {
+		numEnd := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if numEnd == -1 {
+			numEnd = len(s)
+		}
+		if numEnd == 0 {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		unitEnd := strings.IndexFunc(s[numEnd:], func(r rune) bool {
+			return (r >= '0' && r <= '9') || r == '.'
+		})
+		if unitEnd == -1 {
+			unitEnd = len(s) - numEnd
+		}
+		number, unit := s[:numEnd], s[numEnd:numEnd+unitEnd]
+		s = s[numEnd+unitEnd:]
+
+		var part time.Duration
+		switch unit {
+		case "d", "w":
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			day := 24 * time.Hour
+			if unit == "w" {
+				day *= 7
+			}
+			f := n * float64(day)
+			if f >= math.MaxInt64 {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			part = time.Duration(f)
+		default:
+			var err error
+			part, err = time.ParseDuration(number + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+		}
+
+		// every part is positive, so the sum overflows if it exceeds the largest duration
+		if d > math.MaxInt64-part {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		d += part
+	}
+
+	if neg {
+		return -d, nil
+	}
+	return d, nil
+}
+
+// parseDurationValue parses the value of a time.Duration field. With unit set, numbers without a unit
+// are interpreted in that unit. With extended set, or a unit set, ParseDuration is used to accept days and weeks.
+func parseDurationValue(value, unit string, extended bool) (time.Duration, error) {
+	if unit != "" {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			value += unit
+		}
+		return ParseDuration(value)
+	}
+	if extended {
+		return ParseDuration(value)
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,216 @@
+package goenv
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStructTime(t *testing.T) {
+	copenhagen, err := time.LoadLocation("Europe/Copenhagen")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	type config struct {
+		Default  time.Time `goenv:"DEFAULT"`
+		Custom   time.Time `goenv:"CUSTOM,layout=02/01/2006"`
+		Named    time.Time `goenv:"NAMED,layout=RFC1123"`
+		Zoned    time.Time `goenv:"ZONED,layout=DateTime,tz=Europe/Copenhagen"`
+		Offset   time.Time `goenv:"OFFSET,tz=Europe/Copenhagen"`
+		Unix     time.Time `goenv:"UNIX,layout=unix"`
+		Millis   time.Time `goenv:"MILLIS,layout=unixmilli,tz=Europe/Copenhagen"`
+		Midnight time.Time `goenv:"MIDNIGHT,layout=DateOnly,tz=Europe/Copenhagen,default=2025-06-13"`
+	}
+
+	env := map[string]string{
+		"DEFAULT": "1992-06-26",
+		"CUSTOM":  "26/06/1992",
+		"NAMED":   "Fri, 26 Jun 1992 10:00:00 UTC",
+		"ZONED":   "2025-06-13 10:30:00",
+		"OFFSET":  "2025-06-13T10:30:00+05:00",
+		"UNIX":    "1749810600",
+		"MILLIS":  "1749810600500",
+	}
+
+	var cfg config
+	if err := Struct(&cfg, WithEnvironment(env)); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		{"Default", cfg.Default, time.Date(1992, 6, 26, 0, 0, 0, 0, time.UTC)},
+		{"Custom", cfg.Custom, time.Date(1992, 6, 26, 0, 0, 0, 0, time.UTC)},
+		{"Named", cfg.Named, time.Date(1992, 6, 26, 10, 0, 0, 0, time.UTC)},
+		{"Zoned", cfg.Zoned, time.Date(2025, 6, 13, 10, 30, 0, 0, copenhagen)},
+		{"Offset", cfg.Offset, time.Date(2025, 6, 13, 5, 30, 0, 0, time.UTC)},
+		{"Unix", cfg.Unix, time.Date(2025, 6, 13, 10, 30, 0, 0, time.UTC)},
+		{"Millis", cfg.Millis, time.Date(2025, 6, 13, 10, 30, 0, 500_000_000, time.UTC)},
+		{"Midnight", cfg.Midnight, time.Date(2025, 6, 13, 0, 0, 0, 0, copenhagen)},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if cfg.Zoned.Location().String() != copenhagen.String() || cfg.Millis.Location().String() != copenhagen.String() {
+		t.Errorf("Zoned and Millis should be in %v, got %v and %v", copenhagen, cfg.Zoned.Location(), cfg.Millis.Location())
+	}
+
+	marshalled, err := MarshalStruct(cfg)
+	if err != nil {
+		t.Fatalf("MarshalStruct() error = %v", err)
+	}
+	var roundTrip config
+	if err := Struct(&roundTrip, WithEnvironment(marshalled)); err != nil {
+		t.Fatalf("Struct(MarshalStruct()) error = %v", err)
+	}
+	for _, tt := range [][2]time.Time{
+		{roundTrip.Custom, cfg.Custom},
+		{roundTrip.Named, cfg.Named},
+		{roundTrip.Zoned, cfg.Zoned},
+		{roundTrip.Millis, cfg.Millis},
+	} {
+		if !tt[0].Equal(tt[1]) {
+			t.Errorf("round trip = %v, want %v", tt[0], tt[1])
+		}
+	}
+}
+
+func TestStructTimeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		input   any
+		message string
+	}{
+		{
+			name: "Lists attempted layouts",
+			env:  map[string]string{"TIME": "26/06/1992"},
+			input: &struct {
+				Time time.Time `goenv:"TIME"`
+			}{},
+			message: `invalid time value "26/06/1992": tried layouts 2006-01-02T15:04:05Z07:00, 2006-01-02T15:04:05.999999999Z07:00`,
+		},
+		{
+			name: "Custom layout",
+			env:  map[string]string{"TIME": "1992-06-26"},
+			input: &struct {
+				Time time.Time `goenv:"TIME,layout=02/01/2006"`
+			}{},
+			message: `invalid time value "1992-06-26": tried layouts 02/01/2006`,
+		},
+		{
+			name: "Invalid Unix timestamp",
+			env:  map[string]string{"TIME": "yesterday"},
+			input: &struct {
+				Time time.Time `goenv:"TIME,layout=unix"`
+			}{},
+			message: `invalid time value "yesterday": expected a unix timestamp`,
+		},
+		{
+			name: "Unknown time zone",
+			env:  map[string]string{"TIME": "1992-06-26"},
+			input: &struct {
+				Time time.Time `goenv:"TIME,tz=Nowhere/Special"`
+			}{},
+			message: "invalid tz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.input, WithEnvironment(tt.env))
+			if err == nil {
+				t.Fatalf("Struct() error = nil, want error")
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Struct() error = %q, want it to contain %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		fail  bool
+	}{
+		{value: "0", want: 0},
+		{value: "90s", want: 90 * time.Second},
+		{value: "1h30m", want: 90 * time.Minute},
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "1.5d", want: 36 * time.Hour},
+		{value: "2w", want: 14 * 24 * time.Hour},
+		{value: "1w2d12h", want: 9*24*time.Hour + 12*time.Hour},
+		{value: "-1d", want: -24 * time.Hour},
+		{value: "", fail: true},
+		{value: "d", fail: true},
+		{value: "7", fail: true},
+		{value: "7days", fail: true},
+		{value: "99999999999999d", fail: true},
+		{value: "15251w", fail: true},
+		{value: "106751d", want: 106751 * 24 * time.Hour},
+		{value: "106751d24h", fail: true},
+		{value: "2562047h1000000h", fail: true},
+		{value: "-106751d", want: -106751 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestStructExtendedDuration(t *testing.T) {
+	env := map[string]string{
+		"RETENTION": "7d",
+		"TIMEOUT":   "30",
+		"INTERVAL":  "1w",
+		"STRICT":    "7d",
+	}
+
+	var cfg struct {
+		Retention time.Duration `goenv:"RETENTION,days"`
+		Timeout   time.Duration `goenv:"TIMEOUT,unit=s"`
+		Interval  time.Duration `goenv:"INTERVAL,unit=ms"`
+	}
+	if err := Struct(&cfg, WithEnvironment(env)); err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+	if cfg.Retention != 7*24*time.Hour {
+		t.Errorf("Retention = %v, want %v", cfg.Retention, 7*24*time.Hour)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want %v", cfg.Timeout, 30*time.Second)
+	}
+	if cfg.Interval != 7*24*time.Hour {
+		t.Errorf("Interval = %v, want %v", cfg.Interval, 7*24*time.Hour)
+	}
+
+	var strict struct {
+		Strict time.Duration `goenv:"STRICT"`
+	}
+	if err := Struct(&strict, WithEnvironment(env)); err == nil {
+		t.Errorf("Struct() error = nil, want error for days without the days option")
+	}
+
+	var invalid struct {
+		Timeout time.Duration `goenv:"TIMEOUT,unit=parsecs"`
+	}
+	if err := Struct(&invalid, WithEnvironment(env)); err == nil {
+		t.Errorf("Struct() error = nil, want error for invalid unit")
+	}
+}
@@ -0,0 +1,139 @@
+package goenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// UsageFormat selects how Usage renders the environment variables of a struct.
+type UsageFormat int
+
+const (
+	// UsageTable renders an aligned plain text table, suitable for --help output.
+	UsageTable UsageFormat = iota
+	// UsageMarkdown renders a markdown table.
+	UsageMarkdown
+	// UsageJSON renders a JSON array with one object per variable.
+	UsageJSON
+)
+
+type usageEntry struct {
+	Key         string   `json:"key"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description"`
+	Value       string   `json:"value"`
+}
+
+// Usage writes a description of every environment variable the struct v is populated from to w,
+// like flag.PrintDefaults does for flags. For each variable it lists the key, Go type, default,
+// whether it is required, the description from the field's `desc:"..."` tag, and the current value.
+// Current values of fields tagged sensitive are masked. Files named by KEY_FILE variables are not read,
+// the value of such a field is shown as KEY_FILE=path instead.
+//
+// v may be a struct or a pointer to a struct. The options are the same as for Struct.
+func Usage(w io.Writer, v any, format UsageFormat, opts ...Option) error {
+	val, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	o := newOptions(opts)
+	var entries []usageEntry
+	var firstErr error
+	walker := structWalker{
+		opts: o,
+		field: func(f envField, err error) {
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("goenv - error on field %s: %s", f.path, err.Error())
+				}
+				return
+			}
+
+			entries = append(entries, usageEntry{
+				Key:         f.key,
+				Aliases:     f.aliases,
+				Type:        f.value.Type().String(),
+				Default:     f.tag.defaultValue,
+				Required:    f.tag.required,
+				Description: f.field.Tag.Get("desc"),
+				Value:       usageValue(o, f),
+			})
+		},
+	}
+	walker.walk(val, "", o.prefix)
+	if firstErr != nil {
+		return firstErr
+	}
+
+	switch format {
+	case UsageTable:
+		return writeUsageTable(w, entries)
+	case UsageMarkdown:
+		return writeUsageMarkdown(w, entries)
+	case UsageJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if entries == nil {
+			entries = []usageEntry{}
+		}
+		return enc.Encode(entries)
+	}
+
+	return fmt.Errorf("goenv - unknown usage format %d", format)
+}
+
+// usageValue returns the current value of f to show in its usage, from the first of its key and aliases
+// that is set, like Struct reads it. Sensitive values are masked and secret files are named, but not read.
+func usageValue(o *options, f envField) string {
+	for _, key := range append([]string{f.key}, f.aliases...) {
+		if value, _ := o.lookup(key); value != "" {
+			if f.tag.sensitive {
+				return redactedValue
+			}
+			return value
+		}
+		if path, _ := o.lookup(key + "_FILE"); path != "" {
+			return key + "_FILE=" + path
+		}
+	}
+	return ""
+}
+
+func writeUsageTable(w io.Writer, entries []usageEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION\tVALUE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Key, e.Type, e.Default, strconv.FormatBool(e.Required), strings.ReplaceAll(e.Description, "\n", " "), e.Value)
+	}
+	return tw.Flush()
+}
+
+func writeUsageMarkdown(w io.Writer, entries []usageEntry) error {
+	var b strings.Builder
+	b.WriteString("| Key | Type | Default | Required | Description | Value |\n")
+	b.WriteString("|-----|------|---------|----------|-------------|-------|\n")
+	for _, e := range entries {
+		cells := []string{e.Key, e.Type, e.Default, strconv.FormatBool(e.Required), e.Description, e.Value}
+		for i, cell := range cells {
+			cells[i] = markdownCell(cell)
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes s for use in a markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,109 @@
+package goenv
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testUsageConfig struct {
+	Port     int    `goenv:"TEST_PORT,default=8080" desc:"Port to listen on"`
+	Level    string `goenv:"TEST_LEVEL,default=info,oneof=debug|info" desc:"Log level: debug|info"`
+	Password string `goenv:"TEST_DB_PASS,required,sensitive" desc:"Database password"`
+}
+
+func TestUsage(t *testing.T) {
+	os.Setenv("TEST_PORT", "9090")
+	os.Setenv("TEST_DB_PASS", "s3cret")
+	t.Cleanup(func() {
+		os.Unsetenv("TEST_PORT")
+		os.Unsetenv("TEST_DB_PASS")
+	})
+
+	tests := []struct {
+		name   string
+		format UsageFormat
+		want   string
+	}{
+		{
+			name:   "Table",
+			format: UsageTable,
+			want: strings.Join([]string{
+				"KEY           TYPE    DEFAULT  REQUIRED  DESCRIPTION            VALUE",
+				"TEST_PORT     int     8080     false     Port to listen on      9090",
+				"TEST_LEVEL    string  info     false     Log level: debug|info  ",
+				"TEST_DB_PASS  string           true      Database password      ***",
+				"",
+			}, "\n"),
+		},
+		{
+			name:   "Markdown",
+			format: UsageMarkdown,
+			want: strings.Join([]string{
+				"| Key | Type | Default | Required | Description | Value |",
+				"|-----|------|---------|----------|-------------|-------|",
+				"| TEST_PORT | int | 8080 | false | Port to listen on | 9090 |",
+				`| TEST_LEVEL | string | info | false | Log level: debug\|info |  |`,
+				"| TEST_DB_PASS | string |  | true | Database password | *** |",
+				"",
+			}, "\n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := Usage(&b, &testUsageConfig{}, tt.format); err != nil {
+				t.Fatalf("Usage() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Usage() = \n%s\nwant:\n%s", b.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("Secret file", func(t *testing.T) {
+		secret := writeTestFile(t, "level", "debug\n", 0o600)
+		env := map[string]string{"TEST_LEVEL_FILE": secret, "TEST_DB_PASS_FILE": "/run/secrets/missing"}
+
+		var b strings.Builder
+		if err := Usage(&b, &testUsageConfig{}, UsageJSON, WithEnvironment(env)); err != nil {
+			t.Fatalf("Usage() error = %v", err)
+		}
+		var got []usageEntry
+		if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if want := "TEST_LEVEL_FILE=" + secret; got[1].Value != want {
+			t.Errorf("Usage() value = %q, want %q", got[1].Value, want)
+		}
+		if want := "TEST_DB_PASS_FILE=/run/secrets/missing"; got[2].Value != want {
+			t.Errorf("Usage() value = %q, want %q", got[2].Value, want)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var b strings.Builder
+		if err := Usage(&b, testUsageConfig{}, UsageJSON); err != nil {
+			t.Fatalf("Usage() error = %v", err)
+		}
+
+		var got []map[string]any
+		if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		want := map[string]any{
+			"key":         "TEST_DB_PASS",
+			"type":        "string",
+			"default":     "",
+			"required":    true,
+			"description": "Database password",
+			"value":       "***",
+		}
+		if len(got) != 3 || !reflect.DeepEqual(got[2], want) {
+			t.Errorf("Usage() = %v, want third entry %v", got, want)
+		}
+	})
+}
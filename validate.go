@@ -0,0 +1,259 @@
+package goenv
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Validator is implemented by config structs that validate themselves after Struct has populated them,
+// typically to check rules that span several fields.
+//
+// Validate may return a ValidationErrors or *FieldError to point at specific fields. Their Field
+// is taken relative to the validated struct.
+type Validator interface {
+	Validate() error
+}
+
+// validateStruct calls the Validate method of val if it has one, recording its errors under path.
+func (d *decoder) validateStruct(val reflect.Value, path string) {
+	validator, ok := val.Addr().Interface().(Validator)
+	if !ok || promotesValidate(val.Type()) {
+		return
+	}
+
+	err := validator.Validate()
+	if err == nil {
+		return
+	}
+
+	var fieldErrs ValidationErrors
+	var fieldErr *FieldError
+	switch {
+	case errors.As(err, &fieldErrs):
+		for _, fe := range fieldErrs {
+			d.errs = append(d.errs, relativeFieldError(path, fe))
+		}
+	case errors.As(err, &fieldErr):
+		d.errs = append(d.errs, relativeFieldError(path, fieldErr))
+	default:
+		if path == "" {
+			path = val.Type().Name()
+		}
+		d.errs = append(d.errs, &FieldError{Field: path, Err: err})
+	}
+}
+
+// validatorType is the type of the Validator interface.
+var validatorType = reflect.TypeFor[Validator]()
+
+// promotesValidate reports whether the Validate method of the struct type t is promoted from an embedded
+// struct that the walker visits, and so is already validated on its own. Validate is promoted if neither
+// t nor *t declares it, which shows in the wrappers the compiler generates for promoted methods.
+func promotesValidate(t reflect.Type) bool {
+	embedsValidator := false
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Anonymous && f.IsExported() && f.Tag.Get("goenv") != "-" && f.Type.Kind() == reflect.Struct &&
+			!valueStructTypes[f.Type] && reflect.PointerTo(f.Type).Implements(validatorType) {
+			embedsValidator = true
+		}
+	}
+	if !embedsValidator {
+		return false
+	}
+
+	for _, typ := range []reflect.Type{t, reflect.PointerTo(t)} {
+		if m, ok := typ.MethodByName("Validate"); ok {
+			pc := m.Func.Pointer()
+			if file, _ := runtime.FuncForPC(pc).FileLine(pc); file != "<autogenerated>" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// relativeFieldError returns a copy of fe with its Field placed below path.
+func relativeFieldError(path string, fe *FieldError) *FieldError {
+	rel := *fe
+	rel.Field = joinFieldPath(path, fe.Field)
+	return &rel
+}
+
+// rule is a validation option of a goenv struct tag, e.g. "min=1" or "notempty".
+type rule struct {
+	name string
+	arg  string
+	re   *regexp.Regexp
+}
+
+func isRuleName(name string) bool {
+	switch name {
+	case "min", "max", "oneof", "match", "notempty", "file_exists", "url", "hostport":
+		return true
+	}
+	return false
+}
+
+func newRule(name, arg string) (rule, error) {
+	r := rule{name: name, arg: arg}
+
+	switch name {
+	case "min", "max", "oneof":
+		if arg == "" {
+			return rule{}, fmt.Errorf("%s requires a value", name)
+		}
+	case "match":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return rule{}, fmt.Errorf("invalid match pattern %q: %s", arg, err.Error())
+		}
+		r.re = re
+	}
+
+	return r, nil
+}
+
+// validateEmpty checks the rules of an optional field whose variable is not set.
+func validateEmpty(rules []rule) error {
+	for _, r := range rules {
+		if r.name == "notempty" {
+			return fmt.Errorf("must not be empty")
+		}
+	}
+	return nil
+}
+
+// validateField checks value, and field after it has been set from value, against rules.
+// It returns the first rule that fails. Errors show shown instead of value, which is masked for
+// sensitive fields.
+func validateField(field reflect.Value, value, shown string, rules []rule) error {
+	for _, r := range rules {
+		if err := r.validate(field, value, shown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r rule) validate(field reflect.Value, value, shown string) error {
+	switch r.name {
+	case "min":
+		cmp, err := compareBound(field, r.arg)
+		if err != nil {
+			return err
+		}
+		if cmp < 0 {
+			return fmt.Errorf("must be at least %s", r.arg)
+		}
+	case "max":
+		cmp, err := compareBound(field, r.arg)
+		if err != nil {
+			return err
+		}
+		if cmp > 0 {
+			return fmt.Errorf("must be at most %s", r.arg)
+		}
+	case "oneof":
+		for _, option := range strings.Split(r.arg, "|") {
+			if value == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", r.arg)
+	case "match":
+		if !r.re.MatchString(value) {
+			return fmt.Errorf("must match %s", r.arg)
+		}
+	case "notempty":
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("must not be empty")
+		}
+	case "file_exists":
+		if _, err := os.Stat(value); err != nil {
+			// the path of the error is replaced, keeping only its cause
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				return fmt.Errorf("file must exist: %s %s: %w", pathErr.Op, shown, pathErr.Err)
+			}
+			return fmt.Errorf("file must exist: %s", shown)
+		}
+	case "url":
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("must be an absolute URL")
+		}
+	case "hostport":
+		_, port, err := net.SplitHostPort(value)
+		if err != nil {
+			return fmt.Errorf("must be a host:port pair")
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("must have a port between 0 and 65535")
+		}
+	}
+
+	return nil
+}
+
+// compareBound compares the value of field with the bound of a min or max rule.
+// Numbers, durations and byte sizes are compared by value, strings by their length.
+// It returns -1, 0 or +1 as field is less than, equal to or greater than bound.
+func compareBound(field reflect.Value, bound string) (int, error) {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		b, err := ParseDuration(bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration bound %q", bound)
+		}
+		return cmp.Compare(field.Int(), int64(b)), nil
+	}
+
+	if field.Type() == reflect.TypeOf(ByteSize(0)) {
+		b, err := ParseByteSize(bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid byte size bound %q", bound)
+		}
+		return cmp.Compare(field.Uint(), uint64(b)), nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b, err := strconv.ParseInt(bound, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid int bound %q", bound)
+		}
+		return cmp.Compare(field.Int(), b), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b, err := strconv.ParseUint(bound, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid uint bound %q", bound)
+		}
+		return cmp.Compare(field.Uint(), b), nil
+	case reflect.Float32, reflect.Float64:
+		b, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid float bound %q", bound)
+		}
+		return cmp.Compare(field.Float(), b), nil
+	case reflect.String:
+		b, err := strconv.Atoi(bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid length bound %q", bound)
+		}
+		return cmp.Compare(utf8.RuneCountInString(field.String()), b), nil
+	}
+
+	return 0, fmt.Errorf("min and max are not supported for field type %s", field.Kind())
+}
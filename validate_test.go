@@ -0,0 +1,396 @@
+package goenv
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStructValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		input any
+		fail  bool
+	}{
+		{
+			name: "Int within bounds",
+			env:  map[string]string{"TEST_PORT": "8080"},
+			input: &struct {
+				Port int `goenv:"TEST_PORT,min=1,max=65535"`
+			}{},
+		},
+		{
+			name: "Int below min",
+			env:  map[string]string{"TEST_PORT": "0"},
+			input: &struct {
+				Port int `goenv:"TEST_PORT,min=1,max=65535"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Default above max",
+			input: &struct {
+				Port uint `goenv:"TEST_PORT,default=70000,max=65535"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Float above max",
+			env:  map[string]string{"TEST_RATIO": "1.5"},
+			input: &struct {
+				Ratio float64 `goenv:"TEST_RATIO,min=0,max=1"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Duration within bounds",
+			env:  map[string]string{"TEST_TIMEOUT": "30s"},
+			input: &struct {
+				Timeout time.Duration `goenv:"TEST_TIMEOUT,min=1s,max=1m"`
+			}{},
+		},
+		{
+			name: "Duration above max",
+			env:  map[string]string{"TEST_TIMEOUT": "2m"},
+			input: &struct {
+				Timeout time.Duration `goenv:"TEST_TIMEOUT,min=1s,max=1m"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "String length below min",
+			env:  map[string]string{"TEST_NAME": "ab"},
+			input: &struct {
+				Name string `goenv:"TEST_NAME,min=3"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Value in oneof",
+			env:  map[string]string{"TEST_LEVEL": "warn"},
+			input: &struct {
+				Level string `goenv:"TEST_LEVEL,oneof=debug|info|warn"`
+			}{},
+		},
+		{
+			name: "Value not in oneof",
+			env:  map[string]string{"TEST_LEVEL": "trace"},
+			input: &struct {
+				Level string `goenv:"TEST_LEVEL,oneof=debug|info|warn"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Value matches pattern",
+			env:  map[string]string{"TEST_NAME": "service"},
+			input: &struct {
+				Name string `goenv:"TEST_NAME,match=^[a-z]+$"`
+			}{},
+		},
+		{
+			name: "Value does not match pattern",
+			env:  map[string]string{"TEST_NAME": "Service1"},
+			input: &struct {
+				Name string `goenv:"TEST_NAME,match=^[a-z]+$"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Invalid pattern",
+			env:  map[string]string{"TEST_NAME": "service"},
+			input: &struct {
+				Name string `goenv:"TEST_NAME,match=[a-z"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Optional unset value is not validated",
+			input: &struct {
+				Port  int    `goenv:"TEST_PORT,min=1"`
+				Level string `goenv:"TEST_LEVEL,oneof=debug|info"`
+			}{},
+		},
+		{
+			name: "Optional unset value with notempty",
+			input: &struct {
+				Name string `goenv:"TEST_NAME,notempty"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Whitespace value with notempty",
+			env:  map[string]string{"TEST_NAME": "   "},
+			input: &struct {
+				Name string `goenv:"TEST_NAME,notempty"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Existing file",
+			env:  map[string]string{"TEST_FILE": "testdata/.env.ci"},
+			input: &struct {
+				File string `goenv:"TEST_FILE,file_exists"`
+			}{},
+		},
+		{
+			name: "Missing file",
+			env:  map[string]string{"TEST_FILE": "testdata/.env.missing"},
+			input: &struct {
+				File string `goenv:"TEST_FILE,file_exists"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Valid url",
+			env:  map[string]string{"TEST_URL": "https://api.example.com/v1"},
+			input: &struct {
+				URL string `goenv:"TEST_URL,url"`
+			}{},
+		},
+		{
+			name: "Relative url",
+			env:  map[string]string{"TEST_URL": "/v1"},
+			input: &struct {
+				URL string `goenv:"TEST_URL,url"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Valid hostport",
+			env:  map[string]string{"TEST_ADDR": "localhost:8080"},
+			input: &struct {
+				Addr string `goenv:"TEST_ADDR,hostport"`
+			}{},
+		},
+		{
+			name: "Hostport with invalid port",
+			env:  map[string]string{"TEST_ADDR": "localhost:99999"},
+			input: &struct {
+				Addr string `goenv:"TEST_ADDR,hostport"`
+			}{},
+			fail: true,
+		},
+		{
+			name: "Min on unsupported type",
+			env:  map[string]string{"TEST_BOOL": "true"},
+			input: &struct {
+				Flag bool `goenv:"TEST_BOOL,min=1"`
+			}{},
+			fail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			t.Cleanup(func() {
+				for k := range tt.env {
+					os.Unsetenv(k)
+				}
+			})
+
+			err := Struct(tt.input)
+			if tt.fail && err == nil {
+				t.Errorf("Struct() error = nil, fail %v", tt.fail)
+			}
+			if !tt.fail && err != nil {
+				t.Errorf("Struct() error = %v, fail %v", err, tt.fail)
+			}
+		})
+	}
+}
+
+func TestStructValidationErrors(t *testing.T) {
+	os.Setenv("TEST_PORT", "0")
+	os.Setenv("TEST_LEVEL", "trace")
+	t.Cleanup(func() {
+		os.Unsetenv("TEST_PORT")
+		os.Unsetenv("TEST_LEVEL")
+	})
+
+	input := &struct {
+		Port  int    `goenv:"TEST_PORT,min=1"`
+		Level string `goenv:"TEST_LEVEL,oneof=debug|info"`
+	}{}
+
+	var errs ValidationErrors
+	if err := Struct(input); !errors.As(err, &errs) {
+		t.Fatalf("Struct() error = %v, want ValidationErrors", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("len(ValidationErrors) = %d, want 2: %v", len(errs), errs)
+	}
+	if errs[0].Field != "Port" || errs[0].Value != "0" || errs[0].Err.Error() != "must be at least 1" {
+		t.Errorf("errs[0] = %+v", errs[0])
+	}
+	if errs[1].Field != "Level" || errs[1].Value != "trace" || errs[1].Err.Error() != "must be one of debug|info" {
+		t.Errorf("errs[1] = %+v", errs[1])
+	}
+}
+
+type testPoolConfig struct {
+	MinConns int `goenv:"TEST_MIN_CONNS,default=1"`
+	MaxConns int `goenv:"TEST_MAX_CONNS,default=10"`
+}
+
+func (c *testPoolConfig) Validate() error {
+	if c.MinConns > c.MaxConns {
+		return &FieldError{Field: "MinConns", Err: errors.New("must not exceed MaxConns")}
+	}
+	return nil
+}
+
+type testTLSConfig struct {
+	Cert string `goenv:"TEST_TLS_CERT"`
+	Key  string `goenv:"TEST_TLS_KEY"`
+}
+
+func (c testTLSConfig) Validate() error {
+	if (c.Cert == "") != (c.Key == "") {
+		return errors.New("cert and key must both be set or both be empty")
+	}
+	return nil
+}
+
+type testServerConfig struct {
+	Pool testPoolConfig
+	TLS  testTLSConfig
+	Port int `goenv:"TEST_PORT,default=8080,min=1"`
+}
+
+func (c *testServerConfig) Validate() error {
+	if c.TLS.Cert != "" && c.Port == 80 {
+		return errors.New("TLS must not be served on port 80")
+	}
+	return nil
+}
+
+type TestEmbeddedValidator struct {
+	Level string `goenv:"TEST_EMBEDDED_LEVEL"`
+}
+
+func (c TestEmbeddedValidator) Validate() error {
+	if c.Level == "bad" {
+		return errors.New("inner bad")
+	}
+	return nil
+}
+
+type testPromotedValidator struct {
+	TestEmbeddedValidator
+	Port int `goenv:"TEST_EMBEDDED_PORT"`
+}
+
+type testShadowedValidator struct {
+	TestEmbeddedValidator
+	Port int `goenv:"TEST_EMBEDDED_PORT"`
+}
+
+func (c *testShadowedValidator) Validate() error {
+	if c.Port == 0 {
+		return errors.New("outer bad")
+	}
+	return nil
+}
+
+func TestStructValidator_Embedded(t *testing.T) {
+	env := map[string]string{"TEST_EMBEDDED_LEVEL": "bad"}
+	tests := []struct {
+		name   string
+		input  any
+		fields []string
+	}{
+		{name: "Promoted", input: &testPromotedValidator{}, fields: []string{"TestEmbeddedValidator"}},
+		{name: "Declared", input: &testShadowedValidator{}, fields: []string{"TestEmbeddedValidator", "testShadowedValidator"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs ValidationErrors
+			if err := Struct(tt.input, WithEnvironment(env)); !errors.As(err, &errs) {
+				t.Fatalf("Struct() error = %v, want ValidationErrors", err)
+			}
+			var got []string
+			for _, fe := range errs {
+				got = append(got, fe.Field)
+			}
+			if !slices.Equal(got, tt.fields) {
+				t.Errorf("Struct() fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestStructValidator(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		fields []string
+	}{
+		{
+			name: "All validators pass",
+			env:  map[string]string{"TEST_TLS_CERT": "cert.pem", "TEST_TLS_KEY": "key.pem"},
+		},
+		{
+			name:   "Nested pointer receiver with field error",
+			env:    map[string]string{"TEST_MIN_CONNS": "20"},
+			fields: []string{"Pool.MinConns"},
+		},
+		{
+			name:   "Nested value receiver",
+			env:    map[string]string{"TEST_TLS_CERT": "cert.pem"},
+			fields: []string{"TLS"},
+		},
+		{
+			name: "Nested and outer validators are merged with field errors",
+			env: map[string]string{
+				"TEST_MIN_CONNS": "20",
+				"TEST_TLS_CERT":  "cert.pem",
+				"TEST_TLS_KEY":   "key.pem",
+				"TEST_PORT":      "80",
+				"TEST_MAX_CONNS": "many",
+			},
+			fields: []string{"Pool.MaxConns", "Pool.MinConns", "testServerConfig"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			t.Cleanup(func() {
+				for k := range tt.env {
+					os.Unsetenv(k)
+				}
+			})
+
+			var cfg testServerConfig
+			err := Struct(&cfg)
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Struct() error = %v", err)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Struct() error = %v, want ValidationErrors", err)
+			}
+			var got []string
+			for _, fe := range errs {
+				got = append(got, fe.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("error fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package goenv
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// valueStructTypes are struct types that are read from a single variable, rather than walked as nested structs.
+var valueStructTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):     true,
+	reflect.TypeOf(time.Location{}): true,
+	reflect.TypeOf(url.URL{}):       true,
+	reflect.TypeOf(netip.Addr{}):    true,
+	reflect.TypeOf(netip.Prefix{}):  true,
+}
+
+// envField is a struct field that is read from an environment variable.
+type envField struct {
+	// path is the Go path of the field, e.g. "Database.Port".
+	path string
+	// key is the environment variable of the field, including prefixes.
+	key string
+	// aliases are the deprecated keys of the field, including prefixes.
+	aliases []string
+	tag     tagConfig
+	value   reflect.Value
+	field   reflect.StructField
+}
+
+// structWalker visits the fields of a struct the same way Struct reads them,
+// resolving nested structs, prefixes and keys.
+type structWalker struct {
+	opts *options
+	// field is called for every field that is read from an environment variable.
+	// If the tag of the field is invalid, err is set and only the path of f is known.
+	field func(f envField, err error)
+	// leave, if set, is called for every struct after its fields and nested structs have been visited.
+	leave func(val reflect.Value, path string)
+}
+
+// walk visits the fields of val. path is the Go field path of val,
+// while prefix is prepended to the keys of all fields in val.
+func (w *structWalker) walk(val reflect.Value, path, prefix string) {
+	for i := range val.NumField() {
+		field := val.Field(i)
+		structField := val.Type().Field(i)
+		fieldPath := joinFieldPath(path, structField.Name)
+		if !structField.IsExported() {
+			continue
+		}
+
+		tag := structField.Tag.Get("goenv")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Kind() == reflect.Struct && !valueStructTypes[field.Type()] {
+			w.walk(field, fieldPath, w.nestedPrefix(prefix, structField))
+			continue
+		}
+
+		if tag == "" && !w.opts.autoKeys {
+			continue
+		}
+
+		tagConfig, err := parseTag(tag)
+		if err != nil {
+			w.field(envField{path: fieldPath}, err)
+			continue
+		}
+		if tagConfig.key == "" {
+			if !w.opts.autoKeys {
+				w.field(envField{path: fieldPath}, fmt.Errorf("empty env var key"))
+				continue
+			}
+			tagConfig.key = fieldNameToKey(structField.Name)
+		}
+
+		f := envField{path: fieldPath, key: prefix + tagConfig.key, tag: tagConfig, value: field, field: structField}
+		for _, alias := range tagConfig.aliases {
+			f.aliases = append(f.aliases, prefix+alias)
+		}
+		w.field(f, nil)
+	}
+
+	if w.leave != nil {
+		w.leave(val, path)
+	}
+}
+
+// nestedPrefix returns the key prefix for the fields of the nested struct field sf.
+// An envPrefix tag always wins. Otherwise, when keys are derived automatically,
+// the field name becomes part of the prefix, unless the struct is embedded.
+func (w *structWalker) nestedPrefix(prefix string, sf reflect.StructField) string {
+	if p, ok := sf.Tag.Lookup("envPrefix"); ok {
+		return prefix + p
+	}
+	if w.opts.autoKeys && !sf.Anonymous {
+		return prefix + fieldNameToKey(sf.Name) + "_"
+	}
+	return prefix
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// structValue returns the struct v, or the struct v points to.
+func structValue(v any) (reflect.Value, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("goenv - expected struct or pointer to struct")
+	}
+	return val, nil
+}
@@ -0,0 +1,323 @@
+package goenv
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultPollInterval is used where the operating system cannot report changes to files.
+	defaultPollInterval = 2 * time.Second
+	// settleDelay is how long a Watcher waits after a change is reported for writes to the file to finish.
+	settleDelay = 100 * time.Millisecond
+)
+
+// Watcher holds a config struct of type T, loaded from dotenv files by Struct, and reloads it when
+// the files change. It lets long running processes pick up rotated credentials or a new log level
+// without restarting:
+//
+//	w, err := goenv.NewWatcher[Config]([]string{".env"})
+//	if err != nil {
+//		return err
+//	}
+//	go w.Run(ctx)
+//
+//	cfg := w.Load()
+//
+// On Linux the Watcher is notified of changes with inotify, elsewhere it polls the files. A reload
+// reads the files the same way Load does, on top of the environment the process was started with,
+// and populates and validates a new T. Only if that succeeds the new config is published, so Load
+// keeps returning the last valid config while the files are invalid.
+//
+// Files named by KEY_FILE variables are read on every reload, but changes to them do not trigger
+// one. Call Reload, e.g. on SIGHUP, to pick them up.
+//
+// A Watcher is safe for concurrent use.
+type Watcher[T any] struct {
+	filenames []string
+	opts      []Option
+	interval  time.Duration
+	logger    *slog.Logger
+	// base is the environment the files are applied to.
+	base map[string]string
+
+	current atomic.Pointer[T]
+
+	// reloadMu serializes reloads, so callbacks see configs in the order they were published.
+	reloadMu sync.Mutex
+	// files holds the content of the readable files when they were last checked.
+	files map[string]string
+	// env holds the variables read by the last successful reload.
+	env map[string]string
+
+	mu       sync.Mutex
+	onReload []func(old, new *T)
+	onChange []changeSubscription
+	onError  []func(error)
+}
+
+// changeSubscription is a callback registered with OnChange, OnKeyChange or OnFieldChange.
+type changeSubscription struct {
+	// keys are the variables f is interested in, or nil for every variable.
+	keys map[string]bool
+	f    func(ChangeSet)
+}
+
+// NewWatcher loads a config of type T from filenames, with the options of Struct, and returns a Watcher
+// holding it. Call Run to reload the config when the files change.
+//
+// If no files are provided, NewWatcher defaults to ".env". The environment variables of the process
+// are read once, and variables set later by Load or os.Setenv are ignored. Use WithEnvironment to
+// provide the environment yourself.
+func NewWatcher[T any](filenames []string, opts ...Option) (*Watcher[T], error) {
+	if len(filenames) == 0 {
+		filenames = []string{defaultFile()}
+	}
+
+	o := newOptions(opts)
+	base := maps.Clone(o.env)
+	if base == nil {
+		base, _ = OSEnvSource().Load(context.Background())
+	}
+
+	w := &Watcher[T]{
+		filenames: filenames,
+		opts:      opts,
+		interval:  o.pollInterval,
+		logger:    o.logger,
+		base:      base,
+	}
+	w.files = w.readFiles()
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Load returns the current config. It must not be modified, as it is shared by every caller.
+func (w *Watcher[T]) Load() *T {
+	return w.current.Load()
+}
+
+// OnReload registers f to be called with the previous and the new config whenever a changed config
+// is published. Callbacks are called one at a time, from the goroutine that reloaded the config,
+// and must not call Reload.
+func (w *Watcher[T]) OnReload(f func(old, new *T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onReload = append(w.onReload, f)
+}
+
+// OnChange registers f to be called with the variables that changed whenever a changed config is
+// published, after the OnReload callbacks. Values are masked, so the change set can be logged; read
+// the new values from the config. A config that only changed because a file named by a KEY_FILE
+// variable changed is not reported, as no variable changed.
+func (w *Watcher[T]) OnChange(f func(ChangeSet)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, changeSubscription{f: f})
+}
+
+// OnKeyChange registers f like OnChange, but f is only called if one of keys changed, and only with
+// the changes of keys:
+//
+//	w.OnKeyChange([]string{"LOG_LEVEL"}, func(goenv.ChangeSet) {
+//		logger.SetLevel(w.Load().LogLevel)
+//	})
+func (w *Watcher[T]) OnKeyChange(keys []string, f func(ChangeSet)) {
+	subscription := changeSubscription{keys: make(map[string]bool, len(keys)), f: f}
+	for _, key := range keys {
+		subscription.keys[key] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, subscription)
+}
+
+// OnFieldChange registers f like OnKeyChange, for the variables that the fields of T are read from,
+// including their KEY_FILE variables and aliases. Fields are given by their Go path, e.g.
+// "Database.Port", and a nested struct such as "Database" stands for all of its fields.
+//
+// It returns an error if a path is not a field of T that is read from an environment variable.
+func (w *Watcher[T]) OnFieldChange(fields []string, f func(ChangeSet)) error {
+	keys, err := fieldKeys(reflect.New(reflect.TypeFor[T]()).Elem(), fields, newOptions(w.opts))
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, changeSubscription{keys: keys, f: f})
+	return nil
+}
+
+// OnError registers f to be called when Run fails to reload the config. The previous config is kept.
+// Without OnError callbacks, failures are logged to the logger set by WithLogger.
+func (w *Watcher[T]) OnError(f func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = append(w.onError, f)
+}
+
+// Reload reads the files and publishes the new config if it is valid and differs from the current one.
+// If it is not valid, the current config is kept and the error is returned.
+func (w *Watcher[T]) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	env, err := Merge(w.base, false, w.filenames...)
+	if err != nil {
+		return err
+	}
+
+	cfg := new(T)
+	if err := Struct(cfg, append(w.opts[:len(w.opts):len(w.opts)], WithEnvironment(env))...); err != nil {
+		return err
+	}
+
+	// the variables are always recorded, so a change set only holds the changes since the last reload
+	old, oldEnv := w.current.Load(), w.env
+	w.env = env
+	if old != nil && reflect.DeepEqual(old, cfg) {
+		return nil
+	}
+	w.current.Store(cfg)
+	if old == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	callbacks, subscriptions := w.onReload, w.onChange
+	w.mu.Unlock()
+	for _, f := range callbacks {
+		f(old, cfg)
+	}
+
+	changes := Diff(oldEnv, env).Masked()
+	for _, s := range subscriptions {
+		cs := changes
+		if s.keys != nil {
+			cs = changes.filter(s.keys)
+		}
+		if !cs.Empty() {
+			s.f(cs)
+		}
+	}
+	return nil
+}
+
+// Run watches the files and reloads the config when they change, until ctx is done.
+// Run must not be called more than once at a time.
+func (w *Watcher[T]) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var events <-chan struct{}
+	if w.interval <= 0 {
+		// without notifications, e.g. on other operating systems, the files are polled
+		events, _ = notifyChanges(ctx, w.filenames)
+	}
+
+	interval := w.interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	tick := ticker.C
+	if events != nil {
+		tick = nil
+	}
+
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-events:
+			if !ok {
+				events, tick = nil, ticker.C
+				continue
+			}
+			settle = time.After(settleDelay)
+		case <-settle:
+			settle = nil
+			w.check()
+		case <-tick:
+			w.check()
+		}
+	}
+}
+
+// check reloads the config if the content of any of the files changed since the last check.
+func (w *Watcher[T]) check() {
+	files := w.readFiles()
+	if maps.Equal(files, w.files) {
+		return
+	}
+	w.files = files
+
+	if err := w.Reload(); err != nil {
+		w.mu.Lock()
+		callbacks := w.onError
+		w.mu.Unlock()
+		for _, f := range callbacks {
+			f(err)
+		}
+		if len(callbacks) == 0 && w.logger != nil {
+			w.logger.Error("goenv: failed to reload config", "error", err)
+		}
+	}
+}
+
+// readFiles returns the content of every file that can be read.
+func (w *Watcher[T]) readFiles() map[string]string {
+	files := make(map[string]string, len(w.filenames))
+	for _, filename := range w.filenames {
+		if src, err := os.ReadFile(filename); err == nil {
+			files[filename] = string(src)
+		}
+	}
+	return files
+}
+
+// fieldKeys returns the variables that the fields of val at paths are read from.
+func fieldKeys(val reflect.Value, paths []string, opts *options) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	found := make(map[string]bool, len(paths))
+	walker := structWalker{
+		opts: opts,
+		field: func(f envField, err error) {
+			if err != nil {
+				return
+			}
+			for _, path := range paths {
+				if f.path != path && !strings.HasPrefix(f.path, path+".") {
+					continue
+				}
+				found[path] = true
+				for _, key := range append([]string{f.key}, f.aliases...) {
+					keys[key] = true
+					keys[key+"_FILE"] = true
+				}
+			}
+		},
+	}
+	walker.walk(val, "", opts.prefix)
+
+	for _, path := range paths {
+		if !found[path] {
+			return nil, fmt.Errorf("goenv: %s is not a field of %s read from the environment", path, val.Type())
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+package goenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// inotifyMask selects the events of a directory that may change one of its files, including files
+// replaced by a rename, as editors and Kubernetes do.
+const inotifyMask = syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// notifyChanges returns a channel that receives a value when the directory of any of filenames
+// changes, until ctx is done. The directories are watched rather than the files, so files that are
+// replaced or created later are noticed too.
+func notifyChanges(ctx context.Context, filenames []string) (<-chan struct{}, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	watched := make(map[string]bool)
+	for _, filename := range filenames {
+		dir := filepath.Dir(filename)
+		if watched[dir] {
+			continue
+		}
+		if _, err := syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+			syscall.Close(fd)
+			return nil, os.NewSyscallError("inotify_add_watch", err)
+		}
+		watched[dir] = true
+	}
+
+	// a non-blocking descriptor is handled by the runtime poller, so closing it ends a pending Read
+	f := os.NewFile(uintptr(fd), "inotify")
+	events := make(chan struct{}, 1)
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+	go func() {
+		defer close(events)
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			if _, err := f.Read(buf); err != nil {
+				return
+			}
+			select {
+			case events <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package goenv
+
+import (
+	"context"
+	"errors"
+)
+
+// notifyChanges is only supported on Linux, other operating systems poll the files.
+func notifyChanges(ctx context.Context, filenames []string) (<-chan struct{}, error) {
+	return nil, errors.ErrUnsupported
+}
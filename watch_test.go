@@ -0,0 +1,269 @@
+package goenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testWatchConfig struct {
+	LogLevel string `goenv:"WATCH_LOG_LEVEL,oneof=debug|info|warn"`
+	Password string `goenv:"WATCH_PASSWORD,required"`
+	Port     int    `goenv:"WATCH_PORT,default=8080"`
+}
+
+func TestWatcher(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// replace writes the files by renaming a temporary file over them, as editors do
+		replace bool
+	}{
+		{name: "Notify"},
+		{name: "Notify replaced file", replace: true},
+		{name: "Poll", opts: []Option{WithPollInterval(10 * time.Millisecond)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, ".env")
+			write := func(content string) {
+				t.Helper()
+				target := filename
+				if tt.replace {
+					target = filepath.Join(dir, ".env.tmp")
+				}
+				if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				if tt.replace {
+					if err := os.Rename(target, filename); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			write("WATCH_LOG_LEVEL=info\nWATCH_PASSWORD=old\n")
+
+			opts := append(tt.opts, WithEnvironment(map[string]string{"WATCH_PORT": "9090"}))
+			w, err := NewWatcher[testWatchConfig]([]string{filename}, opts...)
+			if err != nil {
+				t.Fatalf("NewWatcher() = failed with error: %v", err)
+			}
+			expected := testWatchConfig{LogLevel: "info", Password: "old", Port: 9090}
+			if got := *w.Load(); got != expected {
+				t.Fatalf("Load() = got %+v, expected %+v", got, expected)
+			}
+
+			reloads := make(chan [2]testWatchConfig, 10)
+			errs := make(chan error, 10)
+			w.OnReload(func(old, new *testWatchConfig) { reloads <- [2]testWatchConfig{*old, *new} })
+			w.OnError(func(err error) { errs <- err })
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- w.Run(ctx) }()
+			defer func() {
+				cancel()
+				if err := <-done; err != nil {
+					t.Errorf("Run() = failed with error: %v", err)
+				}
+			}()
+			// let Run start watching before the files change
+			time.Sleep(50 * time.Millisecond)
+
+			write("WATCH_LOG_LEVEL=debug\nWATCH_PASSWORD=rotated\n")
+			select {
+			case got := <-reloads:
+				updated := testWatchConfig{LogLevel: "debug", Password: "rotated", Port: 9090}
+				if got[0] != expected || got[1] != updated {
+					t.Errorf("OnReload() = got %+v, expected %+v", got, [2]testWatchConfig{expected, updated})
+				}
+				expected = updated
+			case err := <-errs:
+				t.Fatalf("OnError() = got %v, expected a reload", err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("OnReload() = not called after the file changed")
+			}
+
+			write("WATCH_LOG_LEVEL=trace\nWATCH_PASSWORD=rotated\n")
+			select {
+			case err := <-errs:
+				if !strings.Contains(err.Error(), "LogLevel") {
+					t.Errorf("OnError() = got %v, expected an error for LogLevel", err)
+				}
+			case got := <-reloads:
+				t.Fatalf("OnReload() = got %+v, expected an error", got)
+			case <-time.After(5 * time.Second):
+				t.Fatal("OnError() = not called after the file became invalid")
+			}
+			if got := *w.Load(); got != expected {
+				t.Errorf("Load() = got %+v, expected the last valid config %+v", got, expected)
+			}
+		})
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, ".env")
+	if err := os.WriteFile(filename, []byte("WATCH_PASSWORD=secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewWatcher[testWatchConfig]([]string{filename}, WithEnvironment(map[string]string{}))
+	if err != nil {
+		t.Fatalf("NewWatcher() = failed with error: %v", err)
+	}
+	calls := 0
+	w.OnReload(func(old, new *testWatchConfig) { calls++ })
+
+	first := w.Load()
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() = failed with error: %v", err)
+	}
+	if calls != 0 || w.Load() != first {
+		t.Errorf("Reload() = published an unchanged config")
+	}
+
+	if err := os.WriteFile(filename, []byte("WATCH_PORT=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err == nil {
+		t.Error("Reload() = expected an error for the missing WATCH_PASSWORD")
+	}
+	if calls != 0 || w.Load() != first {
+		t.Errorf("Reload() = replaced the config with an invalid one")
+	}
+
+	if err := os.WriteFile(filename, []byte("WATCH_PASSWORD=secret\nWATCH_PORT=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() = failed with error: %v", err)
+	}
+	if calls != 1 || w.Load().Port != 1 {
+		t.Errorf("Reload() = got %d calls and port %d, expected 1 call and port 1", calls, w.Load().Port)
+	}
+}
+
+func TestNewWatcher_Errors(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, ".env")
+
+	if _, err := NewWatcher[testWatchConfig]([]string{filename}); err == nil || !strings.HasPrefix(err.Error(), "goenv: Failed to load file") {
+		t.Errorf("NewWatcher() = got error %v, expected a missing file", err)
+	}
+
+	if err := os.WriteFile(filename, []byte("WATCH_LOG_LEVEL=info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewWatcher[testWatchConfig]([]string{filename}, WithEnvironment(map[string]string{}))
+	if err == nil || !strings.Contains(err.Error(), "Password") {
+		t.Errorf("NewWatcher() = got error %v, expected a missing WATCH_PASSWORD", err)
+	}
+}
+
+type testWatchNestedConfig struct {
+	Log struct {
+		Level  string `goenv:"LEVEL,default=info"`
+		Format string `goenv:"FORMAT,default=text"`
+	} `envPrefix:"LOG_"`
+	Token string `goenv:"TOKEN,sensitive"`
+	Port  int    `goenv:"PORT,alias=HTTP_PORT"`
+}
+
+func TestWatcher_OnChange(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, ".env")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("TOKEN=old\nLOG_LEVEL=info\nHTTP_PORT=80\n")
+
+	w, err := NewWatcher[testWatchNestedConfig]([]string{filename}, WithEnvironment(map[string]string{}), WithLogger(nil))
+	if err != nil {
+		t.Fatalf("NewWatcher() = failed with error: %v", err)
+	}
+
+	got := make(map[string][]ChangeSet)
+	record := func(name string) func(ChangeSet) {
+		return func(cs ChangeSet) { got[name] = append(got[name], cs) }
+	}
+	w.OnChange(record("all"))
+	w.OnKeyChange([]string{"LOG_LEVEL"}, record("level"))
+	if err := w.OnFieldChange([]string{"Log"}, record("log")); err != nil {
+		t.Fatalf("OnFieldChange() = failed with error: %v", err)
+	}
+	if err := w.OnFieldChange([]string{"Port"}, record("port")); err != nil {
+		t.Fatalf("OnFieldChange() = failed with error: %v", err)
+	}
+	if err := w.OnFieldChange([]string{"Log.Missing"}, record("missing")); err == nil {
+		t.Error("OnFieldChange() = expected an error for an unknown field")
+	}
+
+	write("TOKEN=new\nLOG_FORMAT=json\n")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() = failed with error: %v", err)
+	}
+
+	expected := map[string][]ChangeSet{
+		"all": {{
+			Added:   []Change{{Key: "LOG_FORMAT", New: "***"}},
+			Removed: []Change{{Key: "HTTP_PORT", Old: "***"}, {Key: "LOG_LEVEL", Old: "***"}},
+			Changed: []Change{{Key: "TOKEN", Old: "***", New: "***"}},
+		}},
+		"level": {{
+			Added:   []Change{},
+			Removed: []Change{{Key: "LOG_LEVEL", Old: "***"}},
+			Changed: []Change{},
+		}},
+		"log": {{
+			Added:   []Change{{Key: "LOG_FORMAT", New: "***"}},
+			Removed: []Change{{Key: "LOG_LEVEL", Old: "***"}},
+			Changed: []Change{},
+		}},
+		"port": {{
+			Added:   []Change{},
+			Removed: []Change{{Key: "HTTP_PORT", Old: "***"}},
+			Changed: []Change{},
+		}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("OnChange() = got %+v, expected %+v", got, expected)
+	}
+
+	// only the token changes, so the subscriptions for other keys are not called
+	clear(got)
+	write("TOKEN=newer\nLOG_FORMAT=json\n")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() = failed with error: %v", err)
+	}
+	if len(got) != 1 || len(got["all"]) != 1 {
+		t.Errorf("OnChange() = got %+v, expected only the subscription for all keys", got)
+	}
+
+	// a variable the config does not read publishes nothing, and is not reported by a later reload
+	clear(got)
+	write("TOKEN=newer\nLOG_FORMAT=json\nUNUSED=1\n")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() = failed with error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("OnChange() = got %+v, expected no calls for an unused variable", got)
+	}
+	write("TOKEN=newest\nLOG_FORMAT=json\nUNUSED=1\n")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() = failed with error: %v", err)
+	}
+	if keys := got["all"][0].Keys(); !reflect.DeepEqual(keys, []string{"TOKEN"}) {
+		t.Errorf("OnChange() = got changes of %v, expected only TOKEN", keys)
+	}
+}
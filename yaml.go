@@ -0,0 +1,321 @@
+package goenv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlNode is a node of the block style subset of YAML that config files and docker compose
+// files are written in: mappings, sequences and single line scalars.
+type yamlNode struct {
+	kind yamlKind
+	// value is the text of a scalar. null is set for empty scalars, ~ and null.
+	value string
+	null  bool
+	// keys holds the keys of a mapping, in the same order as children.
+	keys     []string
+	children []*yamlNode
+	// line and column locate the key or sequence item the node is the value of.
+	line   int
+	column int
+}
+
+type yamlKind int
+
+const (
+	yamlScalar yamlKind = iota
+	yamlMapping
+	yamlSequence
+)
+
+type yamlLine struct {
+	indent  int
+	content string
+	line    int
+}
+
+type yamlParser struct {
+	// raw holds every line of the source, lines only those with content.
+	raw   []string
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML parses src, which must be a mapping or a sequence in block style.
+// Flow style collections, anchors, tags and multi document streams are not supported.
+func parseYAML(src []byte) (*yamlNode, error) {
+	p := &yamlParser{raw: strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")}
+	for i, line := range p.raw {
+		content := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		content = strings.TrimRight(content, " \t")
+		if content == "" || content[0] == '#' || content == "---" || content == "..." {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{indent: len(line) - len(strings.TrimLeft(line, " ")), content: content, line: i + 1})
+	}
+
+	if len(p.lines) == 0 {
+		return &yamlNode{kind: yamlMapping}, nil
+	}
+	node, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].line)
+	}
+	return node, nil
+}
+
+// parseBlock parses the mapping or sequence starting at the current line, which is indented by indent.
+func (p *yamlParser) parseBlock(indent int) (*yamlNode, error) {
+	if isYAMLItem(p.lines[p.pos].content) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseSequence(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: yamlSequence}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent || !isYAMLItem(l.content) {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.line)
+		}
+
+		rest := strings.TrimLeft(l.content[1:], " ")
+		if strings.HasPrefix(rest, "#") {
+			rest = ""
+		}
+		itemIndent := l.indent + len(l.content) - len(rest)
+		var item *yamlNode
+		var err error
+		switch {
+		case rest == "":
+			p.pos++
+			item, err = p.parseValue(indent)
+		case isYAMLItem(rest) || isYAMLMapping(rest):
+			// a compact collection, as if the item started on the next line
+			p.lines[p.pos] = yamlLine{indent: itemIndent, content: rest, line: l.line}
+			item, err = p.parseBlock(itemIndent)
+		default:
+			p.pos++
+			item, err = parseYAMLScalar(rest, l.line)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		item.line, item.column = l.line, itemIndent+1
+		node.children = append(node.children, item)
+	}
+	return node, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: yamlMapping}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.line)
+		}
+		if isYAMLItem(l.content) {
+			break
+		}
+
+		key, rest, err := splitYAMLKey(l.content, l.line)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(rest, "#") {
+			rest = ""
+		}
+		p.pos++
+
+		var value *yamlNode
+		switch {
+		case rest == "":
+			value, err = p.parseValue(indent)
+		case rest[0] == '|' || rest[0] == '>':
+			value, err = p.parseBlockScalar(indent, rest, l.line)
+		default:
+			value, err = parseYAMLScalar(rest, l.line)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value.line, value.column = l.line, l.indent+1
+		node.keys = append(node.keys, key)
+		node.children = append(node.children, value)
+	}
+	return node, nil
+}
+
+// parseValue parses the value of a key or sequence item that continues on the next lines,
+// which is null if the next line is not indented deeper than parent.
+func (p *yamlParser) parseValue(parent int) (*yamlNode, error) {
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		if next.indent > parent {
+			return p.parseBlock(next.indent)
+		}
+		// a sequence may be indented as deep as the key it belongs to
+		if next.indent == parent && isYAMLItem(next.content) {
+			return p.parseSequence(parent)
+		}
+	}
+	return &yamlNode{kind: yamlScalar, null: true}, nil
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) scalar with the default or strip (-) chomping.
+// Its content is read from the raw lines after line, as it may contain blank lines and '#'.
+func (p *yamlParser) parseBlockScalar(parent int, header string, line int) (*yamlNode, error) {
+	style, chomp := header[0], strings.TrimSpace(header[1:])
+	if i := strings.Index(chomp, "#"); i != -1 {
+		chomp = strings.TrimSpace(chomp[:i])
+	}
+	if chomp != "" && chomp != "-" {
+		return nil, fmt.Errorf("line %d: unsupported block scalar header %q", line, header)
+	}
+
+	var parts []string
+	indent := -1
+	end := line
+	for ; end < len(p.raw); end++ {
+		raw := p.raw[end]
+		content := strings.TrimLeft(raw, " ")
+		if strings.TrimSpace(content) == "" {
+			parts = append(parts, "")
+			continue
+		}
+		lineIndent := len(raw) - len(content)
+		if lineIndent <= parent {
+			break
+		}
+		if indent == -1 {
+			indent = lineIndent
+		}
+		parts = append(parts, strings.Repeat(" ", max(lineIndent-indent, 0))+content)
+	}
+	for len(parts) > 0 && parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	for p.pos < len(p.lines) && p.lines[p.pos].line <= end {
+		p.pos++
+	}
+
+	var value string
+	if style == '|' {
+		value = strings.Join(parts, "\n")
+	} else {
+		for i, part := range parts {
+			switch {
+			case part == "":
+				value += "\n"
+			case i > 0 && parts[i-1] != "":
+				value += " " + part
+			default:
+				value += part
+			}
+		}
+	}
+	if chomp == "" && value != "" {
+		value += "\n"
+	}
+	return &yamlNode{kind: yamlScalar, value: value}, nil
+}
+
+func isYAMLItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+func isYAMLMapping(content string) bool {
+	_, _, err := splitYAMLKey(content, 0)
+	return err == nil
+}
+
+// splitYAMLKey splits "key: value" in to its key and the rest of the line.
+func splitYAMLKey(content string, line int) (string, string, error) {
+	if content[0] == '"' || content[0] == '\'' {
+		key, n, err := readYAMLQuoted(content, line)
+		if err != nil {
+			return "", "", err
+		}
+		rest := content[n:]
+		if rest == ":" || strings.HasPrefix(rest, ": ") {
+			return key, strings.TrimSpace(rest[1:]), nil
+		}
+		return "", "", fmt.Errorf("line %d: expected ':' after key", line)
+	}
+
+	if strings.HasSuffix(content, ":") && !strings.Contains(content, ": ") {
+		return content[:len(content)-1], "", nil
+	}
+	i := strings.Index(content, ": ")
+	if i == -1 || strings.HasPrefix(content, "#") {
+		return "", "", fmt.Errorf("line %d: expected 'key: value'", line)
+	}
+	return content[:i], strings.TrimSpace(content[i+2:]), nil
+}
+
+// parseYAMLScalar parses a plain or quoted scalar that ends on the same line, followed by an optional comment.
+func parseYAMLScalar(content string, line int) (*yamlNode, error) {
+	switch content[0] {
+	case '"', '\'':
+		value, n, err := readYAMLQuoted(content, line)
+		if err != nil {
+			return nil, err
+		}
+		if rest := strings.TrimSpace(content[n:]); rest != "" && rest[0] != '#' {
+			return nil, fmt.Errorf("line %d: unexpected text after quoted value", line)
+		}
+		return &yamlNode{kind: yamlScalar, value: value}, nil
+	case '[', '{':
+		return nil, fmt.Errorf("line %d: flow style collections are not supported", line)
+	case '&', '*', '!':
+		return nil, fmt.Errorf("line %d: anchors, aliases and tags are not supported", line)
+	}
+
+	if i := strings.Index(content, " #"); i != -1 {
+		content = strings.TrimSpace(content[:i])
+	}
+	if content == "~" || content == "null" || content == "Null" || content == "NULL" {
+		return &yamlNode{kind: yamlScalar, null: true}, nil
+	}
+	return &yamlNode{kind: yamlScalar, value: content}, nil
+}
+
+// readYAMLQuoted reads the quoted scalar at the start of content and returns its value and length.
+func readYAMLQuoted(content string, line int) (string, int, error) {
+	quote := content[0]
+	for i := 1; i < len(content); i++ {
+		switch {
+		case quote == '\'' && content[i] == '\'':
+			if i+1 < len(content) && content[i+1] == '\'' {
+				i++
+				continue
+			}
+			return strings.ReplaceAll(content[1:i], "''", "'"), i + 1, nil
+		case quote == '"' && content[i] == '\\':
+			i++
+		case quote == '"' && content[i] == '"':
+			value, err := strconv.Unquote(content[:i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("line %d: invalid escape in quoted value", line)
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("line %d: missing end quote %q", line, quote)
+}